@@ -0,0 +1,157 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+func TestCel(t *testing.T) {
+	tolerance := 1e-12
+
+	for _, kc := range []float64{0.01, 0.3, 0.5, 0.9, 1} {
+		m := 1 - kc*kc
+		if actual, expected := cel(kc, 1, 1, 1), mathext.CompleteK(m); !approxEqual(actual, expected, tolerance) {
+			t.Errorf("kc = %g: expected K = %g, got %g", kc, expected, actual)
+		}
+		if actual, expected := cel(kc, 1, 1, kc*kc), mathext.CompleteE(m); !approxEqual(actual, expected, tolerance) {
+			t.Errorf("kc = %g: expected E = %g, got %g", kc, expected, actual)
+		}
+	}
+	if !math.IsNaN(cel(0, 1, 1, 1)) {
+		t.Errorf("expected NaN for kc = 0")
+	}
+}
+
+func TestCurrentSheetOnAxis(t *testing.T) {
+	tolerance := 1e-15
+	K, a, length := 1000., 0.1, 0.5
+
+	for _, z := range []float64{0, 0.1, 0.25, 1} {
+		expected := mu0 * K / 2 * ((z+length/2)/math.Hypot(z+length/2, a) - (z-length/2)/math.Hypot(z-length/2, a))
+		br, _, bz := CalculateFieldFromCurrentSheetPolar(K, a, length, 0, z)
+		if !approxEqual(bz, expected, tolerance) || br != 0 {
+			t.Errorf("z = %g: expected (0, %g), got (%g, %g)", z, expected, br, bz)
+		}
+	}
+}
+
+func TestCurrentBlockAgreesWithLoopSum(t *testing.T) {
+	relTolerance := 2e-3
+	solenoid := NewSolenoid(0.1, 0.11, 0.3, 50, 0.05, 150, 5)
+
+	tt := []struct {
+		name  string
+		point FieldPoint
+	}{
+		{name: "bore_centre", point: NewPolarPoint(0, 0, 0.05)},
+		{name: "bore_off_axis", point: NewPolarPoint(0.05, 0, 0.1)},
+		{name: "outside_end", point: NewPolarPoint(0.08, 0, 0.4)},
+		{name: "outside_radially", point: NewCartesianPoint(0.15, 0.1, -0.05)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			li, lj, lk := solenoid.CalculateFieldAtPointUsing(tc.point, MethodLoopSum)
+			bi, bj, bk := solenoid.CalculateFieldAtPointUsing(tc.point, MethodCurrentBlock)
+			scale := math.Sqrt(li*li + lj*lj + lk*lk)
+			for i, pair := range [][2]float64{{li, bi}, {lj, bj}, {lk, bk}} {
+				if !approxEqual(pair[0]/scale, pair[1]/scale, relTolerance) {
+					t.Errorf("component %d: loop sum gave %g, current block gave %g", i, pair[0], pair[1])
+				}
+			}
+		})
+	}
+}
+
+func TestOnAxisField(t *testing.T) {
+	solenoid := NewSolenoid(0.1, 0.12, 0.3, 50, 0.05, 150, 5)
+
+	for _, z := range []float64{-1, -0.1, 0, 0.05, 0.2, 0.5} {
+		actual := solenoid.OnAxisField(z)
+		_, _, block := solenoid.CalculateFieldAtPointUsing(NewPolarPoint(0, 0, z), MethodCurrentBlock)
+		if !approxEqual(actual/block, 1, 1e-12) {
+			t.Errorf("z = %g: expected the current block field %g, got %g", z, block, actual)
+		}
+		_, _, loops := solenoid.CalculateFieldAtPointUsing(NewPolarPoint(0, 0, z), MethodLoopSum)
+		// The layers of the loop sum approximate the radial build of the block, which shows far from the coil.
+		if !approxEqual(actual/loops, 1, 5e-4) {
+			t.Errorf("z = %g: expected the loop sum field %g, got %g", z, loops, actual)
+		}
+	}
+
+	// A long solenoid has Bz = mu0 J (Router - Rinner) at its centre.
+	long := NewSolenoid(0.1, 0.12, 100, 50, 0, 1000, 5)
+	if actual, expected := long.OnAxisField(0), mu0*long.CurrentDensity()*0.02; !approxEqual(actual/expected, 1, 1e-5) {
+		t.Errorf("expected %g, got %g", expected, actual)
+	}
+}
+
+func TestCalculateFieldAtPointOnAxis(t *testing.T) {
+	tolerance := 1e-15
+	solenoid := NewSolenoid(0.1, 0.12, 0.3, 50, 0.05, 150, 5)
+	expected := solenoid.OnAxisField(0.1)
+
+	for _, fp := range []FieldPoint{NewPolarPoint(0, 0, 0.1), NewCartesianPoint(0, 0, 0.1)} {
+		for _, calc := range []func(FieldPoint) (float64, float64, float64){solenoid.CalculateFieldAtPoint, solenoid.CalculateFieldAtPointSeq} {
+			bi, bj, bk := calc(fp)
+			if bi != 0 || bj != 0 || !approxEqual(bk, expected, tolerance) {
+				t.Errorf("%T: expected (0, 0, %g), got (%g, %g, %g)", fp, expected, bi, bj, bk)
+			}
+		}
+	}
+
+	// The fast path is taken in the frame of the coil, for points on its axis wherever it is placed.
+	solenoid.Placement = NewPlacement(Vec3{0.1, 0, 0}, NewRotationFromAxisAngle(Vec3{0, 1, 0}, math.Pi/2))
+	bx, by, bz := solenoid.CalculateFieldAtPoint(NewCartesianPoint(0.2, 0, 0))
+	if !approxEqual(bx, expected, tolerance) || !approxEqual(by, 0, tolerance) || !approxEqual(bz, 0, tolerance) {
+		t.Errorf("expected (%g, 0, 0), got (%g, %g, %g)", expected, bx, by, bz)
+	}
+}
+
+func TestOnAxisFieldContinuity(t *testing.T) {
+	tt := []struct {
+		name         string
+		solenoid     *Solenoid
+		relTolerance float64
+	}{
+		// The closed form of the block stands in for the loops of a finely wound coil.
+		{name: "fine", solenoid: NewSolenoid(0.1, 0.12, 0.3, 100, 0, 150, 5), relTolerance: 1e-3},
+		// Coarse and thin windings sum their loops on the axis, which is the limit of the loop sum off it.
+		{name: "coarse", solenoid: NewSolenoid(0.1, 0.12, 0.3, 100, 0, 3, 1), relTolerance: 1e-9},
+		{name: "zero_thickness", solenoid: NewSolenoid(0.1, 0.1, 0.3, 100, 0, 20, 1), relTolerance: 1e-9},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, z := range []float64{-0.5, 0, 0.1, 0.16} {
+				_, _, expected := tc.solenoid.calculateLocalFieldAtPointSeq(NewPolarPoint(1e-9, 0, z))
+				for _, calc := range []func(FieldPoint) (float64, float64, float64){tc.solenoid.CalculateFieldAtPoint, tc.solenoid.CalculateFieldAtPointSeq} {
+					_, _, actual := calc(NewPolarPoint(0, 0, z))
+					if !approxEqual(actual/expected, 1, tc.relTolerance) {
+						t.Errorf("z = %g: expected %g, got %g", z, expected, actual)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOnAxisFieldZeroThickness(t *testing.T) {
+	relTolerance := 1e-12
+	// A winding with no radial build is a current sheet.
+	solenoid := NewSolenoid(0.1, 0.1, 0.3, 100, 0.05, 20, 1)
+
+	for _, z := range []float64{-0.5, 0, 0.05, 0.2} {
+		expected := mu0 * 100 * 20 / 0.3 / 2 * ((z-0.05+0.15)/math.Hypot(0.1, z-0.05+0.15) - (z-0.05-0.15)/math.Hypot(0.1, z-0.05-0.15))
+		actual := solenoid.OnAxisField(z)
+		if !approxEqual(actual/expected, 1, relTolerance) {
+			t.Errorf("z = %g: expected %g, got %g", z, expected, actual)
+		}
+		_, _, block := solenoid.CalculateFieldAtPointUsing(NewPolarPoint(0, 0, z), MethodCurrentBlock)
+		if !approxEqual(block/expected, 1, relTolerance) {
+			t.Errorf("z = %g: expected the current block field %g, got %g", z, expected, block)
+		}
+	}
+}
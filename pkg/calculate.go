@@ -108,3 +108,115 @@ func CalculateFieldFromLoopCartesian(current, a, x, y, z float64) (Bx, By, Bz fl
 	Bz = C / (2 * alpha * alpha * beta) * ((a*a-rho*rho)*E + K)
 	return
 }
+
+// CalculateVectorPotentialFromLoop calculates the azimuthal component of the magnetic vector potential at point (r,phi,z)
+// induced from a current loop.
+//
+// The coordinate (0, 0, 0) lies at the very centre of the current loop and Aphi is the only non-zero component.
+// The potential is given in the Coulomb gauge, so 2πr Aphi is the flux through a coaxial circle of radius r at z.
+// The input variables are:
+//   - current: the current in the loop in amperes
+//   - a: the radius of the current loop in metres
+//   - r: the r coordinate of the point in metres
+//   - z: the z coordinate of the point in metres
+func CalculateVectorPotentialFromLoop(current, a, r, z float64) (Aphi float64) {
+	if r == 0 {
+		// The potential vanishes on the magnetic axis, where the expression below is 0/0.
+		return 0
+	}
+	beta := calculateBeta(a, r, z)
+	// k^2 = 1 - alpha^2/beta^2 is calculated directly, as the subtraction loses precision close to the axis.
+	ksq := 4 * a * r / (beta * beta)
+
+	// (2-k^2)K - 2E cancels to O(k^4) close to the axis, so its series is used there to keep full precision.
+	var ke float64
+	if ksq < 1e-3 {
+		ke = math.Pi * ksq * ksq / 16 * (1 + 3*ksq/4 + 75*ksq*ksq/128)
+	} else {
+		ke = (2-ksq)*mathext.CompleteK(ksq) - 2*mathext.CompleteE(ksq)
+	}
+	return mu0 * current * beta / (4 * math.Pi * r) * ke
+}
+
+// cel calculates Bulirsch's generalised complete elliptic integral
+//
+//	cel(kc, p, c, s) = ∫_0^{π/2} (c cos²φ + s sin²φ) / ((cos²φ + p sin²φ) sqrt(cos²φ + kc² sin²φ)) dφ
+//
+// using the algorithm given by Derby and Olbert (https://arxiv.org/abs/0909.3880).
+// It returns NaN when kc = 0, where the integral diverges.
+func cel(kc, p, c, s float64) float64 {
+	const errTol = 1e-12
+
+	if kc == 0 {
+		return math.NaN()
+	}
+	k := math.Abs(kc)
+	pp, cc, ss, em := p, c, s, 1.0
+	if p > 0 {
+		pp = math.Sqrt(p)
+		ss = s / pp
+	} else {
+		f := kc * kc
+		q := 1 - f
+		g := 1 - pp
+		f -= pp
+		q *= ss - c*pp
+		pp = math.Sqrt(f / g)
+		cc = (c - ss) / g
+		ss = -q/(g*g*pp) + cc*pp
+	}
+
+	f := cc
+	cc += ss / pp
+	g := k / pp
+	ss = 2 * (ss + f*g)
+	pp += g
+	g = em
+	em += k
+	kk := k
+	for math.Abs(g-k) > g*errTol {
+		k = 2 * math.Sqrt(kk)
+		kk = k * em
+		f = cc
+		cc += ss / pp
+		g = kk / pp
+		ss = 2 * (ss + f*g)
+		pp += g
+		g = em
+		em += k
+	}
+	return math.Pi / 2 * (ss + cc*em) / (em * (em + pp))
+}
+
+// CalculateFieldFromCurrentSheetPolar calculates the polar components of the magnetic field at point (r,phi,z)
+// induced from a thin cylindrical current sheet, i.e. a single layer solenoid with infinitely many turns.
+//
+// The coordinate (0, 0, 0) lies at the very centre of the sheet and Bphi is always 0.
+// The expressions are those of Derby and Olbert, which are valid everywhere except on the edges of the sheet.
+// The input variables are:
+//   - surfaceCurrent: the current per unit length of the sheet in amperes per metre (turns per metre times current)
+//   - a: the radius of the sheet in metres
+//   - length: the length of the sheet in metres
+//   - r: the r coordinate of the point in metres
+//   - z: the z coordinate of the point in metres
+func CalculateFieldFromCurrentSheetPolar(surfaceCurrent, a, length, r, z float64) (Br, Bphi, Bz float64) {
+	B0 := mu0 * surfaceCurrent / math.Pi
+	zPlus := z + length/2
+	zMinus := z - length/2
+	gamma := (a - r) / (a + r)
+
+	denomPlus := math.Sqrt(zPlus*zPlus + (r+a)*(r+a))
+	denomMinus := math.Sqrt(zMinus*zMinus + (r+a)*(r+a))
+	kPlus := math.Sqrt(zPlus*zPlus+(a-r)*(a-r)) / denomPlus
+	kMinus := math.Sqrt(zMinus*zMinus+(a-r)*(a-r)) / denomMinus
+
+	alphaPlus := a / denomPlus
+	alphaMinus := a / denomMinus
+	betaPlus := zPlus / denomPlus
+	betaMinus := zMinus / denomMinus
+
+	Br = B0 * (alphaPlus*cel(kPlus, 1, 1, -1) - alphaMinus*cel(kMinus, 1, 1, -1))
+	Bphi = 0
+	Bz = B0 * a / (a + r) * (betaPlus*cel(kPlus, gamma*gamma, 1, gamma) - betaMinus*cel(kMinus, gamma*gamma, 1, gamma))
+	return
+}
@@ -0,0 +1,140 @@
+package golenoid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// This file contains the search for contours of constant magnitude of the field, such as the 5 gauss line
+// that is used for site planning around magnets.
+
+const (
+	// contourTolerance is the relative accuracy to which the distance of every vertex of a contour is found.
+	contourTolerance = 1e-9
+	// contourMaxDistance bounds the search along a ray, in multiples of the size of the source.
+	contourMaxDistance = 1e6
+)
+
+// Contour is a polyline of constant magnitude of the field in the r-z plane at phi = 0.
+type Contour struct {
+	Level  float64       // Magnitude of the field on the contour in teslas
+	Centre float64       // Position on the z-axis the contour was searched from in metres
+	Points []*PolarPoint // Vertices from the positive to the negative z-axis, holding the field at each vertex
+}
+
+// Field returns a Field holding the vertices of the contour, so that it can be written with the field writers.
+func (c *Contour) Field() *Field {
+	field := NewField(len(c.Points))
+	for i, p := range c.Points {
+		field.Points[i] = p
+	}
+	return field
+}
+
+// Extent returns the largest radius and the lowest and highest z reached by the contour in metres.
+func (c *Contour) Extent() (rMax, zMin, zMax float64) {
+	zMin, zMax = math.Inf(1), math.Inf(-1)
+	for _, p := range c.Points {
+		rMax = math.Max(rMax, p.R)
+		zMin = math.Min(zMin, p.Z)
+		zMax = math.Max(zMax, p.Z)
+	}
+	return
+}
+
+// FindFringeContour finds the outermost contour on which the magnitude of the field of src equals level, e.g. 5e-4 T
+// for the 5 gauss line, in the r-z plane at phi = 0.
+//
+// The contour is found along nRays rays from the centre of the bounding box of src on the z-axis, evenly spaced in
+// angle from the positive to the negative z-axis. Each ray is marched outwards past the bounding box until the field
+// stays below level, and the last crossing is then refined by bisection. The rays are searched concurrently.
+func FindFringeContour(src FieldSource, level float64, nRays int) (*Contour, error) {
+	if level <= 0 {
+		return nil, fmt.Errorf("level must be positive, got %g", level)
+	}
+	if nRays < 2 {
+		return nil, fmt.Errorf("need at least 2 rays, got %d", nRays)
+	}
+
+	min, max := src.BoundingBox()
+	centre := (min[2] + max[2]) / 2
+	// The radius of the sphere about the centre that contains the bounding box of the source.
+	size := max.Sub(Vec3{0, 0, centre}).Norm()
+	size = math.Max(size, Vec3{0, 0, centre}.Sub(min).Norm())
+	if size == 0 {
+		return nil, errors.New("source has no extent to search around")
+	}
+
+	contour := &Contour{Level: level, Centre: centre, Points: make([]*PolarPoint, nRays)}
+	angles := NewAxis(0, math.Pi, nRays, true)
+	errs := make([]error, nRays)
+	var wg sync.WaitGroup
+	wg.Add(nRays)
+	for i := 0; i < nRays; i++ {
+		go func(i int) {
+			defer wg.Done()
+			sin, cos := math.Sincos(angles.Value(i))
+			// Rounding would put the ends of the contour a tiny distance off the axis.
+			if i == 0 || i == nRays-1 {
+				sin = 0
+			}
+			contour.Points[i], errs[i] = findCrossing(src, level, centre, sin, cos, size)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return contour, nil
+}
+
+// findCrossing finds the outermost point along the ray from (0, centre) in the direction (sin, cos) in the r-z plane
+// where the magnitude of the field of src falls through level.
+func findCrossing(src FieldSource, level, centre, sin, cos, size float64) (*PolarPoint, error) {
+	at := func(distance float64) (*PolarPoint, float64) {
+		p := NewPolarPoint(distance*sin, 0, centre+distance*cos)
+		Br, Bphi, Bz := calculateFieldSeq(src, p)
+		p.SetFieldPolar(Br, Bphi, Bz)
+		return p, math.Sqrt(Br*Br + Bphi*Bphi + Bz*Bz)
+	}
+
+	// March in steps of a fiftieth of the size of the source, which grow geometrically once past it, remembering the
+	// last step over which the field fell through level.
+	step := size / 50
+	var inner, outer float64
+	found := false
+	previous := 0.
+	_, bPrevious := at(previous)
+	for distance := step; ; distance += step {
+		_, b := at(distance)
+		if bPrevious >= level && b < level {
+			inner, outer, found = previous, distance, true
+		}
+		if found && distance > size && b < level {
+			break
+		}
+		if distance > contourMaxDistance*size {
+			return nil, fmt.Errorf("field does not cross %g T within %g m of the source", level, distance)
+		}
+		previous, bPrevious = distance, b
+		if distance > size {
+			step = math.Max(step, 0.05*distance)
+		}
+	}
+
+	for outer-inner > contourTolerance*outer {
+		mid := (inner + outer) / 2
+		if _, b := at(mid); b >= level {
+			inner = mid
+		} else {
+			outer = mid
+		}
+	}
+	p, _ := at((inner + outer) / 2)
+	return p, nil
+}
@@ -0,0 +1,70 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFindFringeContourDipole(t *testing.T) {
+	// Far from a small loop the field is that of a dipole, |B| = mu0 m sqrt(1 + 3 cos^2 θ) / (4π R^3).
+	relTolerance := 1e-3
+	loop := NewLoop(0.01, 100, 0.2)
+	m := 100 * math.Pi * 0.01 * 0.01
+	level := 5e-9
+
+	contour, err := FindFringeContour(loop, level, 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contour.Points) != 13 || contour.Centre != 0.2 {
+		t.Fatalf("expected 13 points about z = 0.2, got %d about %g", len(contour.Points), contour.Centre)
+	}
+
+	for i, p := range contour.Points {
+		R := math.Hypot(p.R, p.Z-0.2)
+		cos := (p.Z - 0.2) / R
+		expected := math.Cbrt(mu0 * m * math.Sqrt(1+3*cos*cos) / (4 * math.Pi * level))
+		if !approxEqual(R/expected, 1, relTolerance) {
+			t.Errorf("point %d: expected R = %g, got %g", i, expected, R)
+		}
+		if B := math.Sqrt(p.Br*p.Br + p.Bphi*p.Bphi + p.Bz*p.Bz); !approxEqual(B/level, 1, 1e-6) {
+			t.Errorf("point %d: expected |B| = %g, got %g", i, level, B)
+		}
+	}
+
+	rMax, zMin, zMax := contour.Extent()
+	if contour.Points[0].R != 0 || contour.Points[12].R != 0 || zMax != contour.Points[0].Z || zMin != contour.Points[12].Z {
+		t.Errorf("expected the contour to run from the positive to the negative z-axis, got %v", contour.Points)
+	}
+	if rMax <= 0 || rMax >= zMax-0.2 {
+		t.Errorf("expected the dipole contour to reach further along the axis than radially, got rMax = %g and zMax = %g", rMax, zMax)
+	}
+}
+
+func TestFindFringeContourSolenoid(t *testing.T) {
+	s := NewSolenoid(0.25, 0.28, 1.31, 200, 0, 768, 4)
+
+	contour, err := FindFringeContour(s.Using(MethodCurrentBlock), 5e-4, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The 5 gauss line lies outside the solenoid.
+	min, max := s.BoundingBox()
+	for i, p := range contour.Points {
+		if p.R < max[0] && p.Z > min[2] && p.Z < max[2] {
+			t.Errorf("point %d lies inside the solenoid: %v", i, p)
+		}
+	}
+
+	field := contour.Field()
+	if len(field.Points) != 7 || field.Points[3] != contour.Points[3] {
+		t.Errorf("expected the field to hold the contour points")
+	}
+
+	if _, err := FindFringeContour(s, 0, 7); err == nil {
+		t.Errorf("expected an error for a level of 0")
+	}
+	if _, err := FindFringeContour(s, 5e-4, 1); err == nil {
+		t.Errorf("expected an error for a single ray")
+	}
+}
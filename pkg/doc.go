@@ -4,9 +4,11 @@
 // such as those used in particle accelerators or MRI machines. This is due to the mathematics
 // relying on simplified expressions that assume the cross-section of the conductor is negligible.
 //
-// Calculations are performed assuming that the magnetic axis of the solenoid is collinear
-// with the z-axis of the coordinate system, and infact the z-axis runs through the exact centre
-// of the coil.
+// Calculations are performed in the frame of the coil, where the magnetic axis of the solenoid is
+// collinear with the z-axis of the coordinate system, and infact the z-axis runs through the exact centre
+// of the coil. A Solenoid can be given a Placement (a translation and a rotation) to model tilted
+// or misaligned coils, in which case points are transformed into the frame of the coil and the field
+// is rotated back into the global frame.
 //
 // Although assumptions are made, the expressions used to calculate the magnetic field are
 // valid in all space outside the conductor and are exact solutions that satisfy Maxwell's equations.
@@ -15,6 +17,4 @@
 //
 // The mathematics behind the computations are described in the following paper:
 // https://ntrs.nasa.gov/citations/20140002333
-//
-// Future support for rotated solenoids and various transformations is planned.
 package golenoid
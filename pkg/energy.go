@@ -0,0 +1,117 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// This file contains the calculation of the energy stored in the magnetic field of the coils.
+
+// StoredEnergy calculates the energy in joules stored in the field of the solenoid at its Current, ½LI².
+// If the layers of a graded winding carry different currents, it is ½ΣIᵢIⱼMᵢⱼ summed over every pair of turns.
+func (s *Solenoid) StoredEnergy() float64 {
+	return 0.5 * s.turnPairSum(func(a, b Layer) float64 { return a.Current * b.Current })
+}
+
+// StoredEnergy calculates the energy in joules stored in the field of the system, ½IᵀLI, where L is the
+// inductance matrix of the system and I holds the Current of every solenoid. If any solenoid has a graded winding,
+// whose layers can carry different currents, the energy is instead ½ΣIᵢIⱼMᵢⱼ summed over every pair of turns of
+// the system.
+//
+// It has the same requirements on the sources as InductanceMatrix.
+func (m *MagnetSystem) StoredEnergy() (float64, error) {
+	solenoids, err := m.solenoids()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range solenoids {
+		if s.Graded() {
+			return storedEnergyOfTurns(solenoids)
+		}
+	}
+
+	L, err := m.InductanceMatrix()
+	if err != nil {
+		return 0, err
+	}
+	currents := mat.NewVecDense(len(solenoids), nil)
+	for i, s := range solenoids {
+		currents.SetVec(i, s.Current)
+	}
+	return 0.5 * mat.Inner(currents, L, currents), nil
+}
+
+// storedEnergyOfTurns calculates the energy in joules stored in the field of the solenoids, ½ΣIᵢIⱼMᵢⱼ summed over
+// every pair of turns, with the current of every turn taken from its layer.
+func storedEnergyOfTurns(solenoids []*Solenoid) (float64, error) {
+	var E float64
+	for i, s := range solenoids {
+		E += s.StoredEnergy()
+		for j := i + 1; j < len(solenoids); j++ {
+			mutual, err := s.mutualTurnSum(solenoids[j], func(current, otherCurrent float64) float64 {
+				return current * otherCurrent
+			})
+			if err != nil {
+				return 0, fmt.Errorf("sources %d and %d: %w", i, j, err)
+			}
+			E += mutual
+		}
+	}
+	return E, nil
+}
+
+// IntegrateFieldEnergy integrates the energy density B²/2μ0 of the field held by the points of the grid over the
+// volume of the grid, using the trapezoidal rule along every axis. The field must already have been calculated.
+//
+// It is meant as a cross-check of StoredEnergy, so the grid should extend well beyond the coils, as the energy
+// outside the grid is missed. The field of filamentary loops diverges at the windings, so the field of
+// Solenoid.Using(MethodCurrentBlock) gives a far more accurate integral for grids that cover the windings.
+//
+// The grid must be a *CartesianGrid, *CylindricalGrid or *SphericalGrid.
+func IntegrateFieldEnergy(g Grid) (float64, error) {
+	var jacobian func(u, v, w float64) float64
+	switch g.(type) {
+	case *CartesianGrid:
+		jacobian = func(x, y, z float64) float64 { return 1 }
+	case *CylindricalGrid:
+		jacobian = func(r, phi, z float64) float64 { return r }
+	case *SphericalGrid:
+		jacobian = func(rho, theta, phi float64) float64 { return rho * rho * math.Sin(theta) }
+	default:
+		return 0, fmt.Errorf("unsupported grid: %T", g)
+	}
+
+	axes := g.Axes()
+	weights := [3][]float64{trapezoidWeights(axes[0]), trapezoidWeights(axes[1]), trapezoidWeights(axes[2])}
+	dims := g.Dims()
+
+	var energy float64
+	for i := 0; i < dims[0]; i++ {
+		for j := 0; j < dims[1]; j++ {
+			for k := 0; k < dims[2]; k++ {
+				Bx, By, Bz := g.At(i, j, k).GetCartesianField()
+				volume := weights[0][i] * weights[1][j] * weights[2][k] * jacobian(axes[0].Value(i), axes[1].Value(j), axes[2].Value(k))
+				energy += (Bx*Bx + By*By + Bz*Bz) * volume
+			}
+		}
+	}
+	return energy / (2 * mu0), nil
+}
+
+// trapezoidWeights returns the weights of the trapezoidal rule for every value of the axis.
+//
+// An axis without its endpoint is treated as periodic, as for phi from 0 to 2π, so every value has the same weight.
+func trapezoidWeights(a Axis) []float64 {
+	weights := make([]float64, a.N)
+	step := a.Step()
+	for i := range weights {
+		weights[i] = step
+	}
+	if a.Endpoint && a.N > 1 {
+		weights[0] /= 2
+		weights[a.N-1] /= 2
+	}
+	return weights
+}
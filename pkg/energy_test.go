@@ -0,0 +1,78 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStoredEnergy(t *testing.T) {
+	tolerance := 1e-12
+	inner := NewSolenoid(0.1, 0.11, 0.3, 100, 0, 30, 2)
+	outer := NewSolenoid(0.15, 0.16, 0.2, -50, 0.1, 20, 1)
+
+	if actual, expected := inner.StoredEnergy(), 0.5*inner.SelfInductance()*100*100; !approxEqual(actual, expected, tolerance) {
+		t.Errorf("expected %g, got %g", expected, actual)
+	}
+
+	M, _ := inner.MutualInductance(outer)
+	expected := inner.StoredEnergy() + outer.StoredEnergy() + M*100*-50
+	actual, err := NewMagnetSystem(inner, outer).StoredEnergy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(actual, expected, tolerance) {
+		t.Errorf("expected %g, got %g", expected, actual)
+	}
+
+	// For uniform windings the sum over the turns agrees with the inductance matrix.
+	turns, err := storedEnergyOfTurns([]*Solenoid{inner, outer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(turns, actual, tolerance) {
+		t.Errorf("expected the sum over the turns to give %g, got %g", actual, turns)
+	}
+}
+
+func TestIntegrateFieldEnergy(t *testing.T) {
+	// The field has kinks at the edges of the windings, so the integral only converges as the square of the step.
+	relTolerance := 1e-2
+	solenoid := NewSolenoid(0.1, 0.12, 0.2, 100, 0, 100, 10)
+
+	// The field is axisymmetric, so a single value of phi with the weight of the full circle is enough.
+	grid := NewCylindricalGrid(NewAxis(0, 1, 251, true), NewAxis(0, 2*math.Pi, 1, false), NewAxis(-1, 1, 501, true))
+	CalculateFullField(solenoid.Using(MethodCurrentBlock), grid.Field())
+
+	actual, err := IntegrateFieldEnergy(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := solenoid.StoredEnergy(); !approxEqual(actual/expected, 1, relTolerance) {
+		t.Errorf("expected %g, got %g", expected, actual)
+	}
+}
+
+func TestTrapezoidWeights(t *testing.T) {
+	tolerance := 1e-15
+
+	tt := []struct {
+		name     string
+		axis     Axis
+		expected []float64
+	}{
+		{name: "endpoint", axis: NewAxis(0, 1, 3, true), expected: []float64{0.25, 0.5, 0.25}},
+		{name: "periodic", axis: NewAxis(0, 1, 4, false), expected: []float64{0.25, 0.25, 0.25, 0.25}},
+		{name: "single_periodic", axis: NewAxis(0, 2, 1, false), expected: []float64{2}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, w := range trapezoidWeights(tc.axis) {
+				if !approxEqual(w, tc.expected[i], tolerance) {
+					t.Errorf("expected %v, got %v", tc.expected, trapezoidWeights(tc.axis))
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package golenoid
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// CoordinateSystem selects the coordinates and field components that a field is written in.
+type CoordinateSystem int
+
+const (
+	// CoordinatesPolar writes (r, phi, z) and (Br, Bphi, Bz).
+	CoordinatesPolar CoordinateSystem = iota
+	// CoordinatesCartesian writes (x, y, z) and (Bx, By, Bz).
+	CoordinatesCartesian
+)
+
+func (c CoordinateSystem) String() string {
+	switch c {
+	case CoordinatesPolar:
+		return "polar"
+	case CoordinatesCartesian:
+		return "cartesian"
+	default:
+		return fmt.Sprintf("CoordinateSystem(%d)", int(c))
+	}
+}
+
+// columns returns the names and units of the columns written for the coordinate system.
+func (c CoordinateSystem) columns() (names, units []string, err error) {
+	switch c {
+	case CoordinatesPolar:
+		return []string{"r", "phi", "z", "Br", "Bphi", "Bz"}, []string{"m", "rad", "m", "T", "T", "T"}, nil
+	case CoordinatesCartesian:
+		return []string{"x", "y", "z", "Bx", "By", "Bz"}, []string{"m", "m", "m", "T", "T", "T"}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported coordinate system: %v", c)
+	}
+}
+
+// values returns the coordinates and field components of fp in the coordinate system.
+func (c CoordinateSystem) values(fp FieldPoint) [6]float64 {
+	if c == CoordinatesCartesian {
+		x, y, z := fp.GetCartesianCoordinates()
+		Bx, By, Bz := fp.GetCartesianField()
+		return [6]float64{x, y, z, Bx, By, Bz}
+	}
+	r, phi, z := fp.GetPolarCoordinates()
+	Br, Bphi, Bz := fp.GetPolarField()
+	return [6]float64{r, phi, z, Br, Bphi, Bz}
+}
+
+// FileFormat selects the format a field is written in.
+type FileFormat int
+
+const (
+	// FormatCSV is comma separated values preceded by a header of '#' comment lines.
+	FormatCSV FileFormat = iota
+	// FormatJSON is a single JSON object with the header fields and an array of points.
+	FormatJSON
+	// FormatVTK is the legacy ASCII VTK format. It is always written in cartesian coordinates.
+	FormatVTK
+	// FormatVTS is the XML VTK structured grid format. It is always written in cartesian coordinates.
+	FormatVTS
+	// FormatVTU is the XML VTK unstructured grid format. It is always written in cartesian coordinates.
+	FormatVTU
+	// FormatBinary is a compact little endian binary format that stores every value exactly.
+	FormatBinary
+)
+
+func (f FileFormat) String() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatJSON:
+		return "json"
+	case FormatVTK:
+		return "vtk"
+	case FormatVTS:
+		return "vts"
+	case FormatVTU:
+		return "vtu"
+	case FormatBinary:
+		return "binary"
+	default:
+		return fmt.Sprintf("FileFormat(%d)", int(f))
+	}
+}
+
+// sourceDescription returns the description of the source of the field, or "unknown".
+func (f *Field) sourceDescription() string {
+	if f.Source == nil {
+		return "unknown"
+	}
+	return f.Source.Description()
+}
+
+// WriteCSV writes every point of the field to w as CSV, with coordinates and field components in the given coordinate system.
+//
+// The CSV is preceded by comment lines starting with '#' that describe the coordinate system, the units, the source
+// and the shape of the grid if the field is structured.
+func (f *Field) WriteCSV(w io.Writer, coords CoordinateSystem) error {
+	names, units, err := coords.columns()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	unitDescriptions := make([]string, len(names))
+	for i := range names {
+		unitDescriptions[i] = fmt.Sprintf("%s [%s]", names[i], units[i])
+	}
+	fmt.Fprintln(bw, "# golenoid field map")
+	fmt.Fprintf(bw, "# coordinates: %v\n", coords)
+	fmt.Fprintf(bw, "# units: %s\n", strings.Join(unitDescriptions, ", "))
+	fmt.Fprintf(bw, "# source: %s\n", f.sourceDescription())
+	fmt.Fprintf(bw, "# points: %d\n", len(f.Points))
+	if f.IsStructured() {
+		fmt.Fprintf(bw, "# dims: %d %d %d\n", f.Dims[0], f.Dims[1], f.Dims[2])
+	}
+
+	cw := csv.NewWriter(bw)
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	record := make([]string, len(names))
+	for _, fp := range f.Points {
+		for i, v := range coords.values(fp) {
+			record[i] = formatFloat(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// jsonField is the layout of a field written as JSON.
+type jsonField struct {
+	Coordinates string            `json:"coordinates"`
+	Units       map[string]string `json:"units"`
+	Source      string            `json:"source"`
+	Columns     []string          `json:"columns"`
+	Dims        []int             `json:"dims,omitempty"`
+	Points      [][6]jsonValue    `json:"points"`
+}
+
+// jsonValue is a float64 that is written as null when it is NaN or infinite, as JSON has no way to represent those.
+// A null is read back as NaN.
+type jsonValue float64
+
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(v))
+}
+
+func (v *jsonValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = jsonValue(math.NaN())
+		return nil
+	}
+	return json.Unmarshal(data, (*float64)(v))
+}
+
+// WriteJSON writes every point of the field to w as JSON, with coordinates and field components in the given coordinate system.
+//
+// Every point is an array of values in the order given by the "columns" member, alongside the coordinate system,
+// the units of each column and the source. Values that are NaN or infinite, such as the field on a singular point
+// of a source, are written as null.
+func (f *Field) WriteJSON(w io.Writer, coords CoordinateSystem) error {
+	names, units, err := coords.columns()
+	if err != nil {
+		return err
+	}
+
+	out := jsonField{
+		Coordinates: coords.String(),
+		Units:       make(map[string]string, len(names)),
+		Source:      f.sourceDescription(),
+		Columns:     names,
+		Points:      make([][6]jsonValue, len(f.Points)),
+	}
+	for i := range names {
+		out.Units[names[i]] = units[i]
+	}
+	if f.IsStructured() {
+		out.Dims = f.Dims[:]
+	}
+	for i, fp := range f.Points {
+		for j, v := range coords.values(fp) {
+			out.Points[i][j] = jsonValue(v)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// binaryMagic identifies the binary format and binaryVersion its layout.
+const (
+	binaryMagic   = "GLNF"
+	binaryVersion = 1
+)
+
+// binaryHeader is the fixed size header at the start of the binary format.
+// It is followed by the source description and then Points values of 6 float64s each.
+type binaryHeader struct {
+	Magic       [4]byte
+	Version     uint8
+	Coordinates uint8
+	Dims        [3]uint32
+	Points      uint64
+	SourceLen   uint32
+}
+
+// WriteBinary writes every point of the field to w in the compact binary format, with coordinates and field
+// components in the given coordinate system.
+//
+// All values are little endian. The header holds the coordinate system, the shape of the grid (zero if the
+// field is not structured), the number of points and the length of the source description that follows it.
+// Every point is then written as 6 float64s in the same order as the CSV columns.
+func (f *Field) WriteBinary(w io.Writer, coords CoordinateSystem) error {
+	if _, _, err := coords.columns(); err != nil {
+		return err
+	}
+	description := f.sourceDescription()
+
+	header := binaryHeader{
+		Version:     binaryVersion,
+		Coordinates: uint8(coords),
+		Points:      uint64(len(f.Points)),
+		SourceLen:   uint32(len(description)),
+	}
+	copy(header.Magic[:], binaryMagic)
+	if f.IsStructured() {
+		for i, d := range f.Dims {
+			header.Dims[i] = uint32(d)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := binary.Write(bw, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if _, err := bw.WriteString(description); err != nil {
+		return err
+	}
+	for _, fp := range f.Points {
+		if err := binary.Write(bw, binary.LittleEndian, coords.values(fp)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,109 @@
+package golenoid
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func newTestField() *Field {
+	field := NewField(2)
+	field.Points[0] = &PolarPoint{R: 1, Phi: 0, Z: 2, Br: 0.5, Bphi: 0, Bz: 0.25}
+	field.Points[1] = &CartesianPoint{X: 3, Y: 0, Z: -1, Bx: 0.125, By: 0, Bz: 1}
+	field.Source = NewLoop(0.2, 10, 0)
+	return field
+}
+
+func TestWriteCSV(t *testing.T) {
+	tt := []struct {
+		name     string
+		coords   CoordinateSystem
+		expected []string
+	}{
+		{name: "polar", coords: CoordinatesPolar, expected: []string{"r,phi,z,Br,Bphi,Bz", "1,0,2,0.5,0,0.25", "3,0,-1,0.125,0,1"}},
+		{name: "cartesian", coords: CoordinatesCartesian, expected: []string{"x,y,z,Bx,By,Bz", "1,0,2,0.5,0,0.25", "3,0,-1,0.125,0,1"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := newTestField().WriteCSV(&buf, tc.coords); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var header, rows []string
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				if strings.HasPrefix(line, "#") {
+					header = append(header, line)
+				} else {
+					rows = append(rows, line)
+				}
+			}
+			if !strings.Contains(strings.Join(header, "\n"), "# source: Loop{Radius: 0.2 m, Current: 10 A, CentrePos: 0 m}") {
+				t.Errorf("expected the source in the header, got %q", header)
+			}
+			if !strings.Contains(strings.Join(header, "\n"), "# coordinates: "+tc.coords.String()) {
+				t.Errorf("expected the coordinate system in the header, got %q", header)
+			}
+			if len(rows) != len(tc.expected) {
+				t.Fatalf("expected %d rows, got %d", len(tc.expected), len(rows))
+			}
+			for i := range rows {
+				if rows[i] != tc.expected[i] {
+					t.Errorf("row %d: expected %q, got %q", i, tc.expected[i], rows[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestField().WriteJSON(&buf, CoordinatesCartesian); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var actual jsonField
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("could not decode output: %v", err)
+	}
+	if actual.Coordinates != "cartesian" || actual.Units["Bx"] != "T" || actual.Units["x"] != "m" {
+		t.Errorf("unexpected header: %+v", actual)
+	}
+	expected := [][6]float64{{1, 0, 2, 0.5, 0, 0.25}, {3, 0, -1, 0.125, 0, 1}}
+	if len(actual.Points) != len(expected) {
+		t.Fatalf("expected %d points, got %d", len(expected), len(actual.Points))
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if !approxEqual(float64(actual.Points[i][j]), expected[i][j], 1e-15) {
+				t.Errorf("point %d: expected %v, got %v", i, expected[i], actual.Points[i])
+				break
+			}
+		}
+	}
+}
+
+func TestWriteJSONNonFinite(t *testing.T) {
+	field := NewField(1)
+	field.Points[0] = &CartesianPoint{X: 0, Y: 0, Z: 1, Bx: math.NaN(), By: math.Inf(1), Bz: 0.5}
+
+	var buf bytes.Buffer
+	if err := field.WriteJSON(&buf, CoordinatesCartesian); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "null") {
+		t.Errorf("expected non-finite values to be written as null, got %s", buf.String())
+	}
+
+	actual, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	Bx, By, Bz := actual.Points[0].GetCartesianField()
+	if !math.IsNaN(Bx) || !math.IsNaN(By) || Bz != 0.5 {
+		t.Errorf("expected (NaN, NaN, 0.5), got (%v, %v, %v)", Bx, By, Bz)
+	}
+}
@@ -1,8 +1,19 @@
 package golenoid
 
+import (
+	"fmt"
+	"math"
+	"os"
+)
+
 // Field represents a magnetic field as a slice of FieldPoints.
 type Field struct {
 	Points []FieldPoint
+	Source FieldSource // The source the field was calculated from, if known
+
+	// Dims is the shape of the grid the points lie on, with the last index varying fastest in Points.
+	// It is zero if the points do not lie on a structured grid.
+	Dims [3]int
 }
 
 // NewField creates a new Field with n FieldPoints.
@@ -12,20 +23,52 @@ func NewField(n int) *Field {
 	}
 }
 
-// TODO @JoeLanglands figure out a better api to create fields from grids.
+// NewFieldGridPolar creates a Field of exactly nr*nphi*nz PolarPoints on a cylindrical grid, where every axis
+// includes both of its endpoints. The exception is a phi range of a full turn, which excludes phiMax so that
+// it does not repeat phiMin. Use NewCylindricalGrid directly for index aware access to the points.
 func NewFieldGridPolar(rMin, rMax, phiMin, phiMax, zMin, zMax float64, nr, nphi, nz int) *Field {
-	field := &Field{Points: make([]FieldPoint, 0)}
-	for r := rMin; r <= rMax; r += (rMax - rMin) / float64(nr) {
-		for phi := phiMin; phi <= phiMax; phi += (phiMax - phiMin) / float64(nphi) {
-			for z := zMin; z <= zMax; z += (zMax - zMin) / float64(nz) {
-				field.Points = append(field.Points, NewPolarPoint(r, phi, z))
-			}
-		}
-	}
+	fullTurn := math.Abs(math.Abs(phiMax-phiMin)-2*math.Pi) < 1e-12
+
+	return NewCylindricalGrid(
+		NewAxis(rMin, rMax, nr, true),
+		NewAxis(phiMin, phiMax, nphi, !fullTurn),
+		NewAxis(zMin, zMax, nz, true),
+	).Field()
+}
 
-	return field
+// IsStructured reports whether the points of the field lie on a structured grid described by Dims.
+func (f *Field) IsStructured() bool {
+	return f.Dims[0] > 0 && f.Dims[0]*f.Dims[1]*f.Dims[2] == len(f.Points)
 }
 
-func (f *Field) WriteToFile() error {
-	return nil
+// WriteToFile writes every point of the field to the file called name in the given format,
+// with coordinates and field components in the given coordinate system. The VTK formats ignore coords.
+// The file is created if it does not exist and truncated if it does.
+func (f *Field) WriteToFile(name string, format FileFormat, coords CoordinateSystem) (err error) {
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	switch format {
+	case FormatCSV:
+		return f.WriteCSV(file, coords)
+	case FormatJSON:
+		return f.WriteJSON(file, coords)
+	case FormatVTK:
+		return f.WriteVTK(file)
+	case FormatVTS:
+		return f.WriteVTS(file)
+	case FormatVTU:
+		return f.WriteVTU(file)
+	case FormatBinary:
+		return f.WriteBinary(file, coords)
+	default:
+		return fmt.Errorf("unsupported file format: %v", format)
+	}
 }
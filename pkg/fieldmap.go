@@ -0,0 +1,449 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+// Interpolation selects how a FieldMap interpolates between the points of its grid.
+type Interpolation int
+
+const (
+	// InterpolationTrilinear interpolates linearly along each axis from the 8 surrounding points.
+	InterpolationTrilinear Interpolation = iota
+	// InterpolationTricubic interpolates with Catmull-Rom cubics along each axis from the 64 surrounding points.
+	// The interpolated field is continuous with continuous first derivatives.
+	InterpolationTricubic
+)
+
+func (i Interpolation) String() string {
+	switch i {
+	case InterpolationTrilinear:
+		return "trilinear"
+	case InterpolationTricubic:
+		return "tricubic"
+	default:
+		return fmt.Sprintf("Interpolation(%d)", int(i))
+	}
+}
+
+// FieldMap is a field calculated once from a source on a CartesianGrid that answers queries by interpolation.
+//
+// It is meant for callers that need the field millions of times, such as particle trackers, and implements
+// FieldSource so it can be used anywhere a source can. Outside the grid the field is NaN.
+type FieldMap struct {
+	components    [3]*lattice
+	interpolation Interpolation
+	description   string
+}
+
+var (
+	_ FieldSource = (*FieldMap)(nil)
+	_ FieldSource = (*AxisymmetricFieldMap)(nil)
+)
+
+// NewFieldMap calculates the field of src at every point of grid and returns a map that interpolates it.
+func NewFieldMap(src FieldSource, grid *CartesianGrid, interpolation Interpolation) *FieldMap {
+	field := grid.Field()
+	CalculateFullField(src, field)
+
+	var components [3][]float64
+	for i := range components {
+		components[i] = make([]float64, len(field.Points))
+	}
+	for i, fp := range field.Points {
+		components[0][i], components[1][i], components[2][i] = fp.GetCartesianField()
+	}
+
+	m := &FieldMap{
+		interpolation: interpolation,
+		description:   fmt.Sprintf("FieldMap{%v interpolation of %s}", interpolation, src.Description()),
+	}
+	for i := range components {
+		m.components[i] = newLattice(grid.Axes(), components[i])
+	}
+	return m
+}
+
+// Contains reports whether (x, y, z) lies within the grid of the map.
+func (m *FieldMap) Contains(x, y, z float64) bool {
+	return m.components[0].contains([3]float64{x, y, z})
+}
+
+// B returns the interpolated cartesian field at (x, y, z), or NaN if the point lies outside the grid.
+func (m *FieldMap) B(x, y, z float64) (Bx, By, Bz float64) {
+	p := [3]float64{x, y, z}
+	if !m.components[0].contains(p) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	var b [3]float64
+	for i, c := range m.components {
+		switch m.interpolation {
+		case InterpolationTrilinear:
+			b[i] = c.trilinear(p)
+		case InterpolationTricubic:
+			b[i] = c.tricubic(p, -1)
+		default:
+			panic(fmt.Sprintf("Unsupported interpolation: %v", m.interpolation))
+		}
+	}
+	return b[0], b[1], b[2]
+}
+
+// CalculateFieldAtPoint interpolates the field at the point fp.
+func (m *FieldMap) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	x, y, z := fp.GetCartesianCoordinates()
+	Bx, By, Bz := m.B(x, y, z)
+	return cartesianFieldAtPoint(fp, Bx, By, Bz)
+}
+
+// BoundingBox returns the corners of the grid of the map, which is where the map is defined.
+func (m *FieldMap) BoundingBox() (min, max Vec3) {
+	return m.components[0].bounds()
+}
+
+// Description returns a human readable description of the map and the source it was calculated from.
+func (m *FieldMap) Description() string {
+	return m.description
+}
+
+// AxisymmetricFieldMap is a divergence-free field map of a source that is axisymmetric about the z-axis.
+//
+// Rather than interpolating the field, it interpolates f = ψ/r² with bicubics in (r, z), where ψ = r Aphi is
+// the flux function, and takes the field from its derivatives:
+//
+//	Br = -r ∂f/∂z,  Bz = 2f + r ∂f/∂r
+//
+// The interpolated field therefore satisfies ∇·B = 0 exactly everywhere in the map. Outside the map the field is NaN.
+type AxisymmetricFieldMap struct {
+	f           *lattice
+	description string
+}
+
+// NewAxisymmetricFieldMap calculates the flux function of src on the (r, z) grid given by the axes and returns
+// a map that interpolates it. src must be axisymmetric about the z-axis and r.Min must not be negative.
+func NewAxisymmetricFieldMap(src FieldSource, r, z Axis) *AxisymmetricFieldMap {
+	if r.Min < 0 {
+		panic(fmt.Sprintf("the r axis of an axisymmetric field map must not be negative, got %g", r.Min))
+	}
+
+	rs, zs := r.Values(), z.Values()
+	f := make([]float64, len(rs)*len(zs))
+	for j, zj := range zs {
+		// ψ is accumulated cell by cell from the axis, integrating r Bz with Gauss-Legendre quadrature.
+		psi, previous := 0.0, 0.0
+		for i, ri := range rs {
+			psi += fluxBetween(src, previous, ri, zj)
+			previous = ri
+			if ri == 0 {
+				_, _, bz := src.CalculateFieldAtPoint(NewPolarPoint(0, 0, zj))
+				f[i*len(zs)+j] = bz / 2
+			} else {
+				f[i*len(zs)+j] = psi / (ri * ri)
+			}
+		}
+	}
+
+	m := &AxisymmetricFieldMap{
+		f:           newLattice([3]Axis{r, NewAxis(0, 0, 1, true), z}, f),
+		description: fmt.Sprintf("AxisymmetricFieldMap{flux function interpolation of %s}", src.Description()),
+	}
+	if r.Min == 0 {
+		// f is even in r, so the ghost layer inside the axis is a mirror image rather than an extrapolation.
+		m.f.mirrorLower(0)
+	}
+	return m
+}
+
+// fluxBetween returns the integral of r Bz from r0 to r1 at z, i.e. the flux through the annulus divided by 2π.
+func fluxBetween(src FieldSource, r0, r1, z float64) float64 {
+	if r1 <= r0 {
+		return 0
+	}
+	return quad.Fixed(func(r float64) float64 {
+		_, _, bz := src.CalculateFieldAtPoint(NewPolarPoint(r, 0, z))
+		return r * bz
+	}, r0, r1, 8, quad.Legendre{}, 0)
+}
+
+// Contains reports whether (x, y, z) lies within the map.
+func (m *AxisymmetricFieldMap) Contains(x, y, z float64) bool {
+	return m.f.contains([3]float64{math.Hypot(x, y), 0, z})
+}
+
+// B returns the interpolated cartesian field at (x, y, z), or NaN if the point lies outside the map.
+func (m *AxisymmetricFieldMap) B(x, y, z float64) (Bx, By, Bz float64) {
+	r, phi, _ := CartesianToPolarCoords(x, y, z)
+	Br, Bz := m.polarField(r, z)
+	return PolarToCartesianField(Br, 0, Bz, phi)
+}
+
+// polarField returns the interpolated (Br, Bz) at (r, z).
+func (m *AxisymmetricFieldMap) polarField(r, z float64) (Br, Bz float64) {
+	p := [3]float64{r, 0, z}
+	if !m.f.contains(p) {
+		return math.NaN(), math.NaN()
+	}
+	f := m.f.tricubic(p, -1)
+	dfdr := m.f.tricubic(p, 0)
+	dfdz := m.f.tricubic(p, 2)
+	return -r * dfdz, 2*f + r*dfdr
+}
+
+// CalculateFieldAtPoint interpolates the field at the point fp.
+func (m *AxisymmetricFieldMap) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	switch p := fp.(type) {
+	case *PolarPoint:
+		Br, Bz := m.polarField(p.R, p.Z)
+		return Br, 0, Bz
+	default:
+		x, y, z := fp.GetCartesianCoordinates()
+		Bx, By, Bz := m.B(x, y, z)
+		return cartesianFieldAtPoint(fp, Bx, By, Bz)
+	}
+}
+
+// BoundingBox returns the corners of the box that contains the cylinder covered by the map.
+func (m *AxisymmetricFieldMap) BoundingBox() (min, max Vec3) {
+	lo, hi := m.f.bounds()
+	return Vec3{-hi[0], -hi[0], lo[2]}, Vec3{hi[0], hi[0], hi[2]}
+}
+
+// Description returns a human readable description of the map and the source it was calculated from.
+func (m *AxisymmetricFieldMap) Description() string {
+	return m.description
+}
+
+// cartesianFieldAtPoint converts cartesian field components to the coordinate system of fp.
+func cartesianFieldAtPoint(fp FieldPoint, Bx, By, Bz float64) (Bi, Bj, Bk float64) {
+	switch fp.(type) {
+	case *CartesianPoint:
+		return Bx, By, Bz
+	case *PolarPoint:
+		_, phi, _ := fp.GetPolarCoordinates()
+		return CartesianToPolarField(Bx, By, Bz, phi)
+	default:
+		panic(fmt.Sprintf("Unsupported point type: %T", fp))
+	}
+}
+
+// lattice holds values on a structured grid, padded with a ghost layer on every side so that cubic stencils
+// near the edges stay inside the data. Ghost values are linearly extrapolated from the edge.
+type lattice struct {
+	axes   [3]Axis
+	dims   [3]int // Dimensions including the ghost layers
+	values []float64
+}
+
+// newLattice creates a lattice from values given in grid order on the axes.
+func newLattice(axes [3]Axis, values []float64) *lattice {
+	l := &lattice{axes: axes}
+	for i, a := range axes {
+		l.dims[i] = a.N + 2
+	}
+	l.values = make([]float64, l.dims[0]*l.dims[1]*l.dims[2])
+
+	n := 0
+	for i := 0; i < axes[0].N; i++ {
+		for j := 0; j < axes[1].N; j++ {
+			for k := 0; k < axes[2].N; k++ {
+				l.set(i, j, k, values[n])
+				n++
+			}
+		}
+	}
+
+	// Each pass extrapolates along one axis over the whole extent of the axes already padded.
+	for d := 0; d < 3; d++ {
+		lo, hi := [3]int{-1, -1, -1}, [3]int{axes[0].N, axes[1].N, axes[2].N}
+		for e := d + 1; e < 3; e++ {
+			lo[e], hi[e] = 0, axes[e].N-1
+		}
+		lo[d], hi[d] = 0, 0
+		for i := lo[0]; i <= hi[0]; i++ {
+			for j := lo[1]; j <= hi[1]; j++ {
+				for k := lo[2]; k <= hi[2]; k++ {
+					l.extrapolate(d, [3]int{i, j, k})
+				}
+			}
+		}
+	}
+	return l
+}
+
+// extrapolate fills both ghost values along axis d of the line through idx.
+func (l *lattice) extrapolate(d int, idx [3]int) {
+	n := l.axes[d].N
+	at := func(m int) [3]int {
+		p := idx
+		p[d] = m
+		return p
+	}
+	first, last := l.get(at(0)), l.get(at(n-1))
+	if n == 1 {
+		l.setAt(at(-1), first)
+		l.setAt(at(1), first)
+		return
+	}
+	l.setAt(at(-1), 2*first-l.get(at(1)))
+	l.setAt(at(n), 2*last-l.get(at(n-2)))
+}
+
+// mirrorLower replaces the lower ghost layer along axis d with a mirror image of the first layer inside the data.
+func (l *lattice) mirrorLower(d int) {
+	for i := -1; i <= l.axes[0].N; i++ {
+		for j := -1; j <= l.axes[1].N; j++ {
+			for k := -1; k <= l.axes[2].N; k++ {
+				idx := [3]int{i, j, k}
+				if idx[d] != -1 {
+					continue
+				}
+				mirror := idx
+				mirror[d] = 1
+				if l.axes[d].N < 2 {
+					mirror[d] = 0
+				}
+				l.setAt(idx, l.get(mirror))
+			}
+		}
+	}
+}
+
+func (l *lattice) index(idx [3]int) int {
+	return ((idx[0]+1)*l.dims[1]+idx[1]+1)*l.dims[2] + idx[2] + 1
+}
+
+func (l *lattice) get(idx [3]int) float64 {
+	return l.values[l.index(idx)]
+}
+
+func (l *lattice) setAt(idx [3]int, v float64) {
+	l.values[l.index(idx)] = v
+}
+
+func (l *lattice) set(i, j, k int, v float64) {
+	l.setAt([3]int{i, j, k}, v)
+}
+
+// bounds returns the first and last value of each axis.
+func (l *lattice) bounds() (min, max Vec3) {
+	for d, a := range l.axes {
+		min[d], max[d] = a.Value(0), a.Value(a.N-1)
+	}
+	return
+}
+
+// contains reports whether p lies within the bounds of the lattice. Axes with a single value accept any coordinate.
+func (l *lattice) contains(p [3]float64) bool {
+	min, max := l.bounds()
+	for d, a := range l.axes {
+		if a.N < 2 {
+			continue
+		}
+		slack := 1e-12 * (max[d] - min[d])
+		if p[d] < min[d]-slack || p[d] > max[d]+slack {
+			return false
+		}
+	}
+	return true
+}
+
+// locate returns the index of the cell containing x along axis d and the fractional position within it.
+func (l *lattice) locate(d int, x float64) (i int, u float64) {
+	a := l.axes[d]
+	if a.N < 2 {
+		return 0, 0
+	}
+	t := (x - a.Min) / a.Step()
+	i = int(math.Floor(t))
+	if i < 0 {
+		i = 0
+	}
+	if i > a.N-2 {
+		i = a.N - 2
+	}
+	return i, t - float64(i)
+}
+
+// trilinear interpolates linearly along each axis.
+func (l *lattice) trilinear(p [3]float64) float64 {
+	var idx [3]int
+	var w [3][2]float64
+	for d := range p {
+		i, u := l.locate(d, p[d])
+		idx[d] = i
+		w[d] = [2]float64{1 - u, u}
+	}
+
+	var v float64
+	for a := 0; a < 2; a++ {
+		for b := 0; b < 2; b++ {
+			for c := 0; c < 2; c++ {
+				v += w[0][a] * w[1][b] * w[2][c] * l.get([3]int{idx[0] + a, idx[1] + b, idx[2] + c})
+			}
+		}
+	}
+	return v
+}
+
+// catmullRom returns the weights of the 4 points of a Catmull-Rom stencil at fractional position u,
+// or the weights of its derivative with respect to u.
+func catmullRom(u float64, derivative bool) [4]float64 {
+	if derivative {
+		return [4]float64{
+			(-3*u*u + 4*u - 1) / 2,
+			(9*u*u - 10*u) / 2,
+			(-9*u*u + 8*u + 1) / 2,
+			(3*u*u - 2*u) / 2,
+		}
+	}
+	u2, u3 := u*u, u*u*u
+	return [4]float64{
+		(-u3 + 2*u2 - u) / 2,
+		(3*u3 - 5*u2 + 2) / 2,
+		(-3*u3 + 4*u2 + u) / 2,
+		(u3 - u2) / 2,
+	}
+}
+
+// tricubic interpolates with Catmull-Rom cubics along each axis. If deriv is an axis index, the derivative
+// along that axis is returned instead of the value.
+func (l *lattice) tricubic(p [3]float64, deriv int) float64 {
+	var idx [3]int
+	var w [3][4]float64
+	for d := range p {
+		i, u := l.locate(d, p[d])
+		idx[d] = i
+		if l.axes[d].N < 2 {
+			// A single value is constant along the axis, so every derivative along it vanishes.
+			if d == deriv {
+				return 0
+			}
+			w[d] = [4]float64{0, 1, 0, 0}
+			continue
+		}
+		w[d] = catmullRom(u, d == deriv)
+		if d == deriv {
+			for n := range w[d] {
+				w[d][n] /= l.axes[d].Step()
+			}
+		}
+	}
+
+	var v float64
+	for a := 0; a < 4; a++ {
+		for b := 0; b < 4; b++ {
+			for c := 0; c < 4; c++ {
+				weight := w[0][a] * w[1][b] * w[2][c]
+				if weight == 0 {
+					continue
+				}
+				v += weight * l.get([3]int{idx[0] + a - 1, idx[1] + b - 1, idx[2] + c - 1})
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+// linearSource is a FieldSource with a field that is linear in position, which trilinear and tricubic
+// interpolation should both reproduce exactly.
+type linearSource struct{}
+
+func (linearSource) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	x, y, z := fp.GetCartesianCoordinates()
+	return cartesianFieldAtPoint(fp, 1+2*x-y, 3*z, x+y+z)
+}
+
+func (linearSource) BoundingBox() (min, max Vec3) { return }
+
+func (linearSource) Description() string { return "linearSource" }
+
+func TestFieldMapLinear(t *testing.T) {
+	tolerance := 1e-12
+	grid := NewCartesianGrid(NewAxis(-1, 1, 3, true), NewAxis(0, 2, 4, true), NewAxis(-0.5, 0.5, 5, true))
+
+	for _, interpolation := range []Interpolation{InterpolationTrilinear, InterpolationTricubic} {
+		t.Run(interpolation.String(), func(t *testing.T) {
+			m := NewFieldMap(linearSource{}, grid, interpolation)
+			for _, p := range []Vec3{{0.3, 1.7, 0.1}, {-1, 0, -0.5}, {1, 2, 0.5}, {-0.9, 0.05, 0.43}} {
+				expected := Vec3{1 + 2*p[0] - p[1], 3 * p[2], p[0] + p[1] + p[2]}
+				bx, by, bz := m.B(p[0], p[1], p[2])
+				actual := Vec3{bx, by, bz}
+				for i := range actual {
+					if !approxEqual(actual[i], expected[i], tolerance) {
+						t.Errorf("at %v: expected %v, got %v", p, expected, actual)
+						break
+					}
+				}
+			}
+
+			if bx, _, _ := m.B(1.5, 0, 0); !math.IsNaN(bx) || m.Contains(1.5, 0, 0) {
+				t.Errorf("expected NaN outside the grid, got %g", bx)
+			}
+		})
+	}
+}
+
+func TestFieldMapSolenoid(t *testing.T) {
+	solenoid := NewSolenoid(0.1, 0.11, 0.3, 50, 0, 150, 5).Using(MethodCurrentBlock)
+	grid := NewCartesianGrid(NewAxis(-0.06, 0.06, 13, true), NewAxis(-0.06, 0.06, 13, true), NewAxis(-0.2, 0.2, 41, true))
+
+	tt := []struct {
+		interpolation Interpolation
+		relTolerance  float64
+	}{
+		{interpolation: InterpolationTrilinear, relTolerance: 1e-3},
+		{interpolation: InterpolationTricubic, relTolerance: 1e-4},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.interpolation.String(), func(t *testing.T) {
+			m := NewFieldMap(solenoid, grid, tc.interpolation)
+			for _, p := range []*CartesianPoint{NewCartesianPoint(0.013, -0.027, 0.1234), NewCartesianPoint(0.041, 0.002, -0.17)} {
+				ex, ey, ez := solenoid.CalculateFieldAtPoint(p)
+				scale := math.Sqrt(ex*ex + ey*ey + ez*ez)
+				ax, ay, az := m.CalculateFieldAtPoint(p)
+				if math.Abs(ax-ex) > tc.relTolerance*scale || math.Abs(ay-ey) > tc.relTolerance*scale || math.Abs(az-ez) > tc.relTolerance*scale {
+					t.Errorf("at %v: expected (%g, %g, %g), got (%g, %g, %g)", p, ex, ey, ez, ax, ay, az)
+				}
+			}
+		})
+	}
+}
+
+func TestAxisymmetricFieldMap(t *testing.T) {
+	// The field comes from derivatives of the interpolant, which converge more slowly than its values.
+	relTolerance := 5e-4
+	solenoid := NewSolenoid(0.1, 0.11, 0.3, 50, 0, 150, 5).Using(MethodCurrentBlock)
+	m := NewAxisymmetricFieldMap(solenoid, NewAxis(0, 0.08, 33, true), NewAxis(-0.2, 0.2, 81, true))
+
+	for _, p := range []*CartesianPoint{NewCartesianPoint(0, 0, 0.01), NewCartesianPoint(0.013, -0.027, 0.1234), NewCartesianPoint(0.041, 0.032, -0.17)} {
+		ex, ey, ez := solenoid.CalculateFieldAtPoint(p)
+		scale := math.Sqrt(ex*ex + ey*ey + ez*ez)
+		ax, ay, az := m.CalculateFieldAtPoint(p)
+		if math.Abs(ax-ex) > relTolerance*scale || math.Abs(ay-ey) > relTolerance*scale || math.Abs(az-ez) > relTolerance*scale {
+			t.Errorf("at %v: expected (%g, %g, %g), got (%g, %g, %g)", p, ex, ey, ez, ax, ay, az)
+		}
+	}
+
+	// The divergence of the interpolated field vanishes, up to the error of the finite differences.
+	h := 1e-6
+	x, y, z := 0.023, 0.011, 0.047
+	bxp, _, _ := m.B(x+h, y, z)
+	bxm, _, _ := m.B(x-h, y, z)
+	_, byp, _ := m.B(x, y+h, z)
+	_, bym, _ := m.B(x, y-h, z)
+	_, _, bzp := m.B(x, y, z+h)
+	_, _, bzm := m.B(x, y, z-h)
+	div := (bxp-bxm)/(2*h) + (byp-bym)/(2*h) + (bzp-bzm)/(2*h)
+	if math.Abs(div) > 1e-6 {
+		t.Errorf("expected zero divergence, got %g T/m", div)
+	}
+}
@@ -0,0 +1,162 @@
+package golenoid
+
+import "math"
+
+// This file contains the calculation of the Lorentz forces on the windings of a solenoid.
+// Every turn is treated as a filamentary loop carrying the current of its layer, so the force on a length dl of a turn
+// is I dl × B.
+
+// forceSegments is the number of straight segments each turn is split into when the force on it is integrated.
+// The integrand is periodic, so the midpoint rule converges very quickly.
+const forceSegments = 64
+
+// AxialForce calculates the net force in newtons on the solenoid along its axis due to the field of other.
+//
+// The solenoids must share the same magnetic axis, otherwise ErrNotCoaxial is returned. The force is positive
+// if it pushes the solenoid towards the positive end of its own axis. Coils carrying current in the same
+// direction attract, and the force on other is equal and opposite.
+func (s *Solenoid) AxialForce(other *Solenoid) (float64, error) {
+	offset, sign, err := coaxialOffset(other.Placement, s.Placement)
+	if err != nil {
+		return 0, err
+	}
+
+	// The calculation is done in the frame of other, where the turns of the solenoid carry sign times their current.
+	// The force along the axis of other is then multiplied by sign to give it along the axis of the solenoid,
+	// so sign cancels out.
+	var F float64
+	s.forEachLoop(func(a, z, current float64) {
+		zOther := offset + sign*z
+		other.forEachLoop(func(b, zLoop, otherCurrent float64) {
+			Br, _, _ := CalculateFieldFromLoopPolar(otherCurrent, b, a, zOther-zLoop)
+			F -= current * 2 * math.Pi * a * Br
+		})
+	})
+	return F, nil
+}
+
+// NetForce calculates the net force in newtons on the solenoid in the global frame due to the field of from.
+//
+// Unlike AxialForce, the solenoid and from can be arbitrarily placed, e.g. to find the decentering force on a
+// misaligned coil. Every turn is split into straight segments and I dl × B is summed over them. If both are
+// axisymmetric about the z-axis the force is purely axial and only the field at one point on each turn is needed.
+//
+// from must not contain the solenoid itself, as the field of a filament diverges at the filament.
+func (s *Solenoid) NetForce(from FieldSource) Vec3 {
+	if s.Axisymmetric() && isAxisymmetric(from) {
+		return s.axisymmetricNetForce(from)
+	}
+
+	var F Vec3
+	dphi := 2 * math.Pi / forceSegments
+	s.forEachLoop(func(a, z, current float64) {
+		for k := 0; k < forceSegments; k++ {
+			phi := (float64(k) + 0.5) * dphi
+			sin, cos := math.Sincos(phi)
+			position := Vec3{a * cos, a * sin, z}
+			dl := Vec3{-a * sin * dphi, a * cos * dphi, 0}
+			if s.Placement != nil {
+				position = s.Placement.ToGlobal(position)
+				dl = s.Placement.Rotation.Apply(dl)
+			}
+
+			Bx, By, Bz := calculateFieldSeq(from, NewCartesianPoint(position[0], position[1], position[2]))
+			F = F.Add(dl.Cross(Vec3{Bx, By, Bz}).Scale(current))
+		}
+	})
+	return F
+}
+
+// axisymmetricNetForce calculates the net force on a solenoid that is axisymmetric about the z-axis due to the
+// field of an axisymmetric source. Only Br contributes, so the force on a turn of radius a is -I 2πa Br along z.
+func (s *Solenoid) axisymmetricNetForce(from FieldSource) Vec3 {
+	origin, direction := s.Placement.axis()
+	// A solenoid flipped by its placement carries its current the other way around the z-axis.
+	sign := math.Copysign(1, direction[2])
+
+	var F float64
+	s.forEachLoop(func(a, z, current float64) {
+		Br, _, _ := calculateFieldSeq(from, NewPolarPoint(a, 0, origin[2]+sign*z))
+		F -= sign * current * 2 * math.Pi * a * Br
+	})
+	return Vec3{0, 0, F}
+}
+
+// LoopForce is the Lorentz force on one turn of a solenoid, in the frame of the coil.
+type LoopForce struct {
+	Layer   int     // Index of the layer, counting outwards from Rinner or in the order of Layers
+	Turn    int     // Index of the turn in the layer, counting from the negative end of the coil
+	Radius  float64 // Radius of the turn in metres
+	Z       float64 // Position of the turn along the axis in metres
+	Current float64 // Current in the turn in amperes
+
+	Br float64 // Radial field at the turn in teslas
+	Bz float64 // Axial field at the turn in teslas
+
+	Fr float64 // Radial force per unit length of conductor in newtons per metre, positive outwards
+	Fz float64 // Axial force per unit length of conductor in newtons per metre
+}
+
+// HoopTension returns the tension in newtons in the turn due to its radial force, Fr times Radius.
+func (f LoopForce) HoopTension() float64 {
+	return f.Fr * f.Radius
+}
+
+// AxialForce returns the net axial force in newtons on the turn, Fz times its circumference.
+func (f LoopForce) AxialForce() float64 {
+	return 2 * math.Pi * f.Radius * f.Fz
+}
+
+// WindingForces calculates the Lorentz force density on every turn of the solenoid due to its own field and the
+// field of the external sources, ordered by layer and then by turn.
+//
+// The field of a filament diverges at the filament, so the field of the solenoid itself is calculated with
+// MethodCurrentBlock, which is finite inside the winding. The field of the external sources is evaluated at
+// phi = 0 in the frame of the coil, so it should be axisymmetric about the axis of the solenoid; use NetForce
+// for the net force due to fields that are not.
+func (s *Solenoid) WindingForces(external ...FieldSource) []LoopForce {
+	from := NewMagnetSystem(external...)
+	var forces []LoopForce
+	for i, l := range s.windingLayers() {
+		a := l.Radius
+		for j := 0; j < l.Nturns; j++ {
+			z := l.turnZ(s.CentrePos, j)
+			Br, Bz := s.fieldInWinding(from, a, z)
+
+			forces = append(forces, LoopForce{
+				Layer:   i,
+				Turn:    j,
+				Radius:  a,
+				Z:       z,
+				Current: l.Current,
+				Br:      Br,
+				Bz:      Bz,
+				// I phi × (Br r + Bz z) = I Bz r - I Br z
+				Fr: l.Current * Bz,
+				Fz: -l.Current * Br,
+			})
+		}
+	}
+	return forces
+}
+
+// fieldInWinding calculates the radial and axial field at radius a and z in the frame of the coil, due to the
+// solenoid itself as a block of uniform current density and the sources in from, at phi = 0.
+func (s *Solenoid) fieldInWinding(from *MagnetSystem, a, z float64) (Br, Bz float64) {
+	Br, Bz = s.blockField(a, z-s.CentrePos)
+	if len(from.Sources) == 0 {
+		return
+	}
+
+	position := Vec3{a, 0, z}
+	if s.Placement != nil {
+		position = s.Placement.ToGlobal(position)
+	}
+	bx, by, bz := from.CalculateFieldAtPointSeq(NewCartesianPoint(position[0], position[1], position[2]))
+	b := Vec3{bx, by, bz}
+	if s.Placement != nil {
+		b = s.Placement.Rotation.ApplyInverse(b)
+	}
+	// At phi = 0 the radial direction is x.
+	return Br + b[0], Bz + b[2]
+}
@@ -0,0 +1,117 @@
+package golenoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAxialForce(t *testing.T) {
+	// The force on a coil is I1 I2 dM/dz, where z is the position of the coil.
+	relTolerance := 1e-6
+	h := 1e-6
+	inner := NewSolenoid(0.1, 0.11, 0.3, 100, 0.02, 30, 2)
+	outer := NewSolenoid(0.15, 0.16, 0.2, 80, 0.1, 20, 1)
+
+	F, err := inner.AxialForce(outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shifted := *inner
+	shifted.CentrePos = inner.CentrePos + h
+	Mplus, _ := shifted.MutualInductance(outer)
+	shifted.CentrePos = inner.CentrePos - h
+	Mminus, _ := shifted.MutualInductance(outer)
+	expected := 100 * 80 * (Mplus - Mminus) / (2 * h)
+	if !approxEqual(F/expected, 1, relTolerance) {
+		t.Errorf("expected %g, got %g", expected, F)
+	}
+
+	if reaction, _ := outer.AxialForce(inner); !approxEqual(reaction/F, -1, 1e-12) {
+		t.Errorf("expected reaction %g, got %g", -F, reaction)
+	}
+	if net := inner.NetForce(outer); !approxEqual(net[2]/F, 1, 1e-12) || net[0] != 0 || net[1] != 0 {
+		t.Errorf("expected net force (0, 0, %g), got %v", F, net)
+	}
+
+	// Flipping the outer coil about its centre reverses its current, and so the force.
+	outer.CentrePos = 0
+	outer.Placement = NewPlacement(Vec3{0, 0, 0.1}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, math.Pi))
+	if flipped, _ := inner.AxialForce(outer); !approxEqual(flipped/F, -1, 1e-9) {
+		t.Errorf("expected %g after flipping, got %g", -F, flipped)
+	}
+
+	outer.Placement = NewPlacement(Vec3{0.01, 0, 0}, IdentityRotation())
+	if _, err := inner.AxialForce(outer); !errors.Is(err, ErrNotCoaxial) {
+		t.Errorf("expected ErrNotCoaxial, got %v", err)
+	}
+}
+
+func TestNetForce(t *testing.T) {
+	relTolerance := 1e-9
+	a := NewSolenoid(0.1, 0.11, 0.2, 100, 0, 10, 2)
+	b := NewSolenoid(0.15, 0.16, 0.1, -60, 0.05, 8, 1)
+
+	// Moving the solenoid off the axis by a negligible distance forces the segment integration.
+	coaxial := a.NetForce(b)
+	a.Placement = NewPlacement(Vec3{0, 1e-9, 0}, NewRotationFromAxisAngle(Vec3{0, 0, 1}, 0.3))
+	segments := a.NetForce(b)
+	if !approxEqual(segments[2]/coaxial[2], 1, 1e-6) {
+		t.Errorf("expected %v, got %v", coaxial, segments)
+	}
+
+	// Misaligned coils obey Newton's third law and feel a decentering force.
+	a.Placement = NewPlacement(Vec3{0.01, 0.005, 0.02}, NewRotationFromEuler(0.05, 0, 0))
+	F := a.NetForce(b)
+	reaction := b.NetForce(a)
+	scale := F.Norm()
+	for i := range F {
+		if !approxEqual(F[i]/scale, -reaction[i]/scale, relTolerance) {
+			t.Errorf("expected %v to be opposite to %v", F, reaction)
+			break
+		}
+	}
+	if math.Abs(F[0]) < 1e-6*scale {
+		t.Errorf("expected a decentering force, got %v", F)
+	}
+}
+
+func TestWindingForces(t *testing.T) {
+	tolerance := 1e-9
+	s := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 30, 4)
+
+	forces := s.WindingForces()
+	if len(forces) != 120 {
+		t.Fatalf("expected 120 forces, got %d", len(forces))
+	}
+
+	var Fz float64
+	for _, f := range forces {
+		Fz += f.AxialForce()
+		// The winding is symmetric about its centre, so the axial forces are antisymmetric.
+		mirror := forces[f.Layer*s.Nturns+s.Nturns-1-f.Turn]
+		if !approxEqual(f.Fz, -mirror.Fz, tolerance) || !approxEqual(f.Fr, mirror.Fr, tolerance) {
+			t.Errorf("layer %d turn %d: expected mirrored forces, got %+v and %+v", f.Layer, f.Turn, f, mirror)
+		}
+	}
+	if !approxEqual(Fz, 0, tolerance) {
+		t.Errorf("expected no net axial self force, got %g", Fz)
+	}
+
+	// The inner layer sees the bore field and is pushed outwards, while the ends are pulled towards the centre.
+	inner, end := forces[s.Nturns/2], forces[0]
+	if inner.Fr <= 0 || inner.HoopTension() <= 0 {
+		t.Errorf("expected the inner layer to be pushed outwards, got %+v", inner)
+	}
+	if end.Fz <= 0 {
+		t.Errorf("expected the end turn to be pulled towards the centre, got %+v", end)
+	}
+
+	// An external field adds to the self field.
+	background := NewSolenoid(0.5, 0.51, 2, 1000, 0, 200, 1)
+	withBackground := s.WindingForces(background)
+	_, _, Bz := background.CalculateFieldAtPointSeq(NewPolarPoint(inner.Radius, 0, inner.Z))
+	if actual := withBackground[s.Nturns/2].Bz; !approxEqual(actual, inner.Bz+Bz, tolerance) {
+		t.Errorf("expected Bz = %g, got %g", inner.Bz+Bz, actual)
+	}
+}
@@ -0,0 +1,205 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// This file contains the description of graded windings, where every layer of a solenoid can have its own radius,
+// axial extent, turn count and current. Graded inserts and notched coils are built from such layers.
+
+// Layer describes one layer of the winding of a Solenoid. Its turns are evenly spaced along its length, each at the
+// centre of its share of the length.
+type Layer struct {
+	Radius    float64 // Radius of the turns in metres, at the centre of the radial build of the layer
+	Thickness float64 // Radial build of the layer in metres
+	Length    float64 // Axial extent of the layer in metres
+	Offset    float64 // Position of the centre of the layer along the axis relative to CentrePos in metres
+	Nturns    int     // Number of turns in the layer
+
+	// Current is the current in every turn of the layer in amperes. It is ignored if the layer is powered in Series.
+	// A layer that is not in Series and has no Current is unpowered, e.g. a spare layer of an insert.
+	Current float64
+	// Series reports whether the layer is powered in series with the solenoid, so that it carries the Current of the
+	// solenoid rather than its own.
+	Series bool
+}
+
+// CurrentDensity returns the current density in the layer in amperes per square metre, as if its Current was
+// spread uniformly over its cross-section. It is infinite for a layer with no Thickness, which is a thin current sheet.
+func (l Layer) CurrentDensity() float64 {
+	return float64(l.Nturns) * l.Current / (l.Length * l.Thickness)
+}
+
+// SetCurrentDensity sets the Current of the layer so that its current density is j in amperes per square metre,
+// taking the layer out of Series.
+func (l *Layer) SetCurrentDensity(j float64) {
+	l.Current = j * l.Length * l.Thickness / float64(l.Nturns)
+	l.Series = false
+}
+
+// pitch returns the axial distance between the turns of the layer.
+func (l Layer) pitch() float64 {
+	return l.Length / float64(l.Nturns)
+}
+
+// turnZ returns the z position of turn j of the layer in the frame of a coil centred at centre.
+func (l Layer) turnZ(centre float64, j int) float64 {
+	return centre + l.Offset - l.Length/2 + (float64(j)+0.5)*l.pitch()
+}
+
+// validate checks that the layer describes a winding that the field can be calculated for.
+func (l Layer) validate() error {
+	if l.Nturns < 1 {
+		return fmt.Errorf("layer must have at least 1 turn, got %d", l.Nturns)
+	}
+	if l.Length <= 0 {
+		return fmt.Errorf("layer must have a positive length, got %g m", l.Length)
+	}
+	if l.Thickness < 0 || l.Radius-l.Thickness/2 < 0 {
+		return fmt.Errorf("layer must have a non-negative thickness within its radius, got %g m at %g m", l.Thickness, l.Radius)
+	}
+	return nil
+}
+
+// NewGradedSolenoid creates a new Solenoid centred at centre whose winding is made of the given layers,
+// carrying current in every layer powered in Series.
+//
+// Every layer must have at least one turn and a positive Length. A layer with no Thickness is a thin current sheet.
+func NewGradedSolenoid(current, centre float64, layers ...Layer) (*Solenoid, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("graded solenoid must have at least 1 layer")
+	}
+	for i, l := range layers {
+		if err := l.validate(); err != nil {
+			return nil, fmt.Errorf("layer %d: %w", i, err)
+		}
+	}
+	return &Solenoid{
+		Current:   current,
+		CentrePos: centre,
+		Layers:    layers,
+	}, nil
+}
+
+// Graded reports whether the winding of the solenoid is described by its Layers.
+func (s *Solenoid) Graded() bool {
+	return len(s.Layers) > 0
+}
+
+// windingLayers returns every layer of the winding with its Current resolved: the Layers of a graded winding,
+// or Nlayers equal layers filling the space between Rinner and Router otherwise.
+func (s *Solenoid) windingLayers() []Layer {
+	layers := make([]Layer, 0, s.Nlayers+len(s.Layers))
+	if s.Graded() {
+		for _, l := range s.Layers {
+			if l.Series {
+				l.Current = s.Current
+			}
+			layers = append(layers, l)
+		}
+		return layers
+	}
+
+	thickness := (s.Router - s.Rinner) / float64(s.Nlayers)
+	for i := 0; i < s.Nlayers; i++ {
+		layers = append(layers, Layer{
+			// Each layer sits at the centre of its share of the height.
+			Radius:    s.Rinner + (float64(i)+0.5)*thickness,
+			Thickness: thickness,
+			Length:    s.Length,
+			Nturns:    s.Nturns,
+			Current:   s.Current,
+		})
+	}
+	return layers
+}
+
+// withCurrent returns a copy of the solenoid carrying current, with the layers that are not in Series scaled in
+// proportion, as if the whole winding was ramped together. Such layers are left as they are if the solenoid carries
+// no current, as there is nothing to scale them by.
+func (s *Solenoid) withCurrent(current float64) *Solenoid {
+	c := *s
+	c.Current = current
+	if s.Graded() {
+		c.Layers = make([]Layer, len(s.Layers))
+		copy(c.Layers, s.Layers)
+		if s.Current != 0 {
+			for i := range c.Layers {
+				c.Layers[i].Current *= current / s.Current
+			}
+		}
+	}
+	return &c
+}
+
+// block is a region of the winding of uniform current density in the (r, z) plane of the coil.
+type block struct {
+	aMin, aMax float64 // Inner and outer radius in metres
+	length     float64 // Axial extent in metres
+	z          float64 // Position of the centre along the axis relative to CentrePos in metres
+	current    float64 // Total current through the cross-section in amperes, i.e. turns times current
+}
+
+// thin reports whether the block has no radial build, so that it is a current sheet of radius aMin.
+func (b block) thin() bool {
+	return b.aMax == b.aMin
+}
+
+// j returns the current density in the block in amperes per square metre.
+func (b block) j() float64 {
+	return b.current / (b.length * (b.aMax - b.aMin))
+}
+
+// surfaceCurrent returns the current per unit length of the block in amperes per metre.
+func (b block) surfaceCurrent() float64 {
+	return b.current / b.length
+}
+
+// blocks returns the winding as blocks of uniform current density: a single block for a uniform winding, and one
+// block per layer for a graded one.
+func (s *Solenoid) blocks() []block {
+	if !s.Graded() {
+		return []block{{aMin: s.Rinner, aMax: s.Router, length: s.Length, current: float64(s.Nturns*s.Nlayers) * s.Current}}
+	}
+	layers := s.windingLayers()
+	blocks := make([]block, len(layers))
+	for i, l := range layers {
+		blocks[i] = block{
+			aMin:    l.Radius - l.Thickness/2,
+			aMax:    l.Radius + l.Thickness/2,
+			length:  l.Length,
+			z:       l.Offset,
+			current: float64(l.Nturns) * l.Current,
+		}
+	}
+	return blocks
+}
+
+// envelope returns the inner and outer radius and the lowest and highest z, relative to CentrePos, of the winding.
+func (s *Solenoid) envelope() (rMin, rMax, zMin, zMax float64) {
+	rMin, zMin = math.Inf(1), math.Inf(1)
+	rMax, zMax = math.Inf(-1), math.Inf(-1)
+	for _, b := range s.blocks() {
+		rMin = math.Min(rMin, b.aMin)
+		rMax = math.Max(rMax, b.aMax)
+		zMin = math.Min(zMin, b.z-b.length/2)
+		zMax = math.Max(zMax, b.z+b.length/2)
+	}
+	return
+}
+
+// describeLayers returns a human readable description of the Layers of a graded winding.
+func (s *Solenoid) describeLayers() string {
+	layers := make([]string, len(s.Layers))
+	for i, l := range s.Layers {
+		current := fmt.Sprintf("%g A", l.Current)
+		if l.Series {
+			current = "series"
+		}
+		layers[i] = fmt.Sprintf("{Radius: %g m, Thickness: %g m, Length: %g m, Offset: %g m, Nturns: %d, Current: %s}",
+			l.Radius, l.Thickness, l.Length, l.Offset, l.Nturns, current)
+	}
+	return strings.Join(layers, ", ")
+}
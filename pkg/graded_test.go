@@ -0,0 +1,255 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+// uniformLayers returns the layers of a uniform winding, as they would be given to a graded solenoid.
+func uniformLayers(s *Solenoid) []Layer {
+	thickness := (s.Router - s.Rinner) / float64(s.Nlayers)
+	layers := make([]Layer, s.Nlayers)
+	for i := range layers {
+		layers[i] = Layer{
+			Radius:    s.Rinner + (float64(i)+0.5)*thickness,
+			Thickness: thickness,
+			Length:    s.Length,
+			Nturns:    s.Nturns,
+			Series:    true,
+		}
+	}
+	return layers
+}
+
+// newTestGradedSolenoid creates a graded solenoid, failing the test if the layers are invalid.
+func newTestGradedSolenoid(t *testing.T, current, centre float64, layers ...Layer) *Solenoid {
+	t.Helper()
+	s, err := NewGradedSolenoid(current, centre, layers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestGradedMatchesUniform(t *testing.T) {
+	relTolerance := 1e-9
+	uniform := NewSolenoid(0.1, 0.13, 0.2, 150, 0.05, 40, 3)
+	graded := newTestGradedSolenoid(t, 150, 0.05, uniformLayers(uniform)...)
+
+	for _, fp := range []FieldPoint{
+		NewPolarPoint(0.05, 0, 0.1),
+		NewCartesianPoint(0.02, -0.03, -0.2),
+		NewPolarPoint(0, 0, 0.3),
+	} {
+		for _, method := range []Method{MethodLoopSum, MethodCurrentBlock} {
+			ui, uj, uk := uniform.CalculateFieldAtPointUsing(fp, method)
+			gi, gj, gk := graded.CalculateFieldAtPointUsing(fp, method)
+			for _, c := range [][2]float64{{ui, gi}, {uj, gj}, {uk, gk}} {
+				if !approxEqual(c[0], c[1], relTolerance*math.Abs(uk)) {
+					t.Errorf("%v at %v: expected %g, got %g", method, fp, c[0], c[1])
+				}
+			}
+		}
+		_, _, ub := uniform.CalculateFieldAtPoint(fp)
+		_, _, gb := graded.CalculateFieldAtPoint(fp)
+		if !approxEqual(ub, gb, relTolerance*math.Abs(ub)) {
+			t.Errorf("field at %v: expected %g, got %g", fp, ub, gb)
+		}
+	}
+
+	for _, tc := range []struct {
+		name             string
+		expected, actual float64
+	}{
+		{"current_density", uniform.CurrentDensity(), graded.CurrentDensity()},
+		{"on_axis", uniform.OnAxisField(0.1), graded.OnAxisField(0.1)},
+		{"self_inductance", uniform.SelfInductance(), graded.SelfInductance()},
+		{"stored_energy", uniform.StoredEnergy(), graded.StoredEnergy()},
+	} {
+		if !approxEqual(tc.actual/tc.expected, 1, relTolerance) {
+			t.Errorf("%s: expected %g, got %g", tc.name, tc.expected, tc.actual)
+		}
+	}
+
+	uMin, uMax := uniform.BoundingBox()
+	gMin, gMax := graded.BoundingBox()
+	for i := range uMin {
+		if !approxEqual(uMin[i], gMin[i], 1e-12) || !approxEqual(uMax[i], gMax[i], 1e-12) {
+			t.Errorf("expected a bounding box of %v to %v, got %v to %v", uMin, uMax, gMin, gMax)
+		}
+	}
+}
+
+func TestGradedLayerCurrents(t *testing.T) {
+	relTolerance := 1e-9
+	// An insert with a high current inner layer and an outer layer that carries the current of the solenoid.
+	inner := Layer{Radius: 0.05, Thickness: 0.004, Length: 0.1, Nturns: 30, Current: 400}
+	outer := Layer{Radius: 0.08, Thickness: 0.004, Length: 0.2, Offset: 0.02, Nturns: 50, Series: true}
+	graded := newTestGradedSolenoid(t, 250, 0, inner, outer)
+
+	// The graded winding is the superposition of a solenoid for each layer.
+	innerCoil := NewSolenoid(0.048, 0.052, 0.1, 400, 0, 30, 1)
+	outerCoil := NewSolenoid(0.078, 0.082, 0.2, 250, 0.02, 50, 1)
+	pair := NewMagnetSystem(innerCoil, outerCoil)
+
+	for _, fp := range []FieldPoint{NewPolarPoint(0.02, 0, 0.03), NewPolarPoint(0.2, 0, -0.1), NewPolarPoint(0, 0, 0.07)} {
+		gr, _, gz := graded.CalculateFieldAtPointSeq(fp)
+		pr, _, pz := pair.CalculateFieldAtPointSeq(fp)
+		if !approxEqual(gr, pr, relTolerance*math.Abs(pz)) || !approxEqual(gz, pz, relTolerance*math.Abs(pz)) {
+			t.Errorf("field at %v: expected (%g, %g), got (%g, %g)", fp, pr, pz, gr, gz)
+		}
+	}
+
+	energy, err := pair.StoredEnergy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := graded.StoredEnergy(); !approxEqual(actual/energy, 1, relTolerance) {
+		t.Errorf("stored energy: expected %g, got %g", energy, actual)
+	}
+
+	force, err := outerCoil.AxialForce(innerCoil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The outer layer is offset from the inner one, so the layers pull each other along the axis.
+	var fz float64
+	for _, f := range graded.WindingForces() {
+		if f.Layer == 1 {
+			fz += f.AxialForce()
+			if f.Current != 250 {
+				t.Fatalf("expected the outer layer to carry 250 A, got %g A", f.Current)
+			}
+		}
+	}
+	// The self force of the outer layer on itself vanishes, leaving the force due to the inner layer.
+	if !approxEqual(fz, force, 1e-3*math.Abs(force)) {
+		t.Errorf("axial force on the outer layer: expected %g N, got %g N", force, fz)
+	}
+
+	min, max := graded.BoundingBox()
+	if !approxEqual(max[0], 0.082, 1e-12) || !approxEqual(min[2], -0.08, 1e-12) || !approxEqual(max[2], 0.12, 1e-12) {
+		t.Errorf("expected the bounding box to enclose both layers, got %v to %v", min, max)
+	}
+}
+
+func TestNotchedCoil(t *testing.T) {
+	// A notch in the middle of a layer is modelled as two layers at the same radius either side of it.
+	left := Layer{Radius: 0.1, Thickness: 0.01, Length: 0.08, Offset: -0.06, Nturns: 16, Series: true}
+	right := left
+	right.Offset = 0.06
+	notched := newTestGradedSolenoid(t, 100, 0, left, right)
+
+	// Summing the loops on the axis agrees with the closed form of the blocks.
+	for _, z := range []float64{0, 0.06, 0.2} {
+		_, _, loops := notched.CalculateFieldAtPointUsing(NewPolarPoint(0, 0, z), MethodLoopSum)
+		if axis := notched.OnAxisField(z); !approxEqual(axis/loops, 1, 1e-3) {
+			t.Errorf("on-axis field at z = %g: expected %g, got %g", z, loops, axis)
+		}
+	}
+
+	// The notch lowers the field at the centre compared to a full layer with the same current density.
+	full := newTestGradedSolenoid(t, 100, 0, Layer{Radius: 0.1, Thickness: 0.01, Length: 0.2, Nturns: 40, Series: true})
+	if notched.OnAxisField(0) >= full.OnAxisField(0) {
+		t.Errorf("expected the notch to lower the central field, got %g and %g", notched.OnAxisField(0), full.OnAxisField(0))
+	}
+
+	if _, err := notched.Stress(Conductor{Width: 0.006, Height: 0.01}); err == nil {
+		t.Errorf("expected an error for a conductor wider than the turn pitch")
+	}
+	report, err := notched.Stress(Conductor{Width: 0.005, Height: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Turns) != 32 || len(report.Layers) != 2 {
+		t.Errorf("expected 32 turns in 2 layers, got %d in %d", len(report.Turns), len(report.Layers))
+	}
+}
+
+func TestLayerCurrentDensity(t *testing.T) {
+	l := Layer{Radius: 0.1, Thickness: 0.01, Length: 0.2, Nturns: 40}
+	l.SetCurrentDensity(1e8)
+	if !approxEqual(l.Current, 5000, 1e-9) {
+		t.Errorf("expected a current of 5000 A, got %g A", l.Current)
+	}
+	if !approxEqual(l.CurrentDensity(), 1e8, 1e-3) {
+		t.Errorf("expected a current density of 1e8 A/m², got %g", l.CurrentDensity())
+	}
+}
+
+func TestUnpoweredLayer(t *testing.T) {
+	relTolerance := 1e-12
+	// A layer that is neither in series nor has a current of its own carries no current.
+	powered := Layer{Radius: 0.05, Thickness: 0.004, Length: 0.1, Nturns: 30, Series: true}
+	spare := Layer{Radius: 0.08, Thickness: 0.004, Length: 0.1, Nturns: 30}
+	graded := newTestGradedSolenoid(t, 200, 0, powered, spare)
+	alone := newTestGradedSolenoid(t, 200, 0, powered)
+
+	for _, fp := range []FieldPoint{NewPolarPoint(0.02, 0, 0.03), NewPolarPoint(0, 0, 0.07)} {
+		gr, _, gz := graded.CalculateFieldAtPointSeq(fp)
+		ar, _, az := alone.CalculateFieldAtPointSeq(fp)
+		if !approxEqual(gr, ar, relTolerance*math.Abs(az)) || !approxEqual(gz, az, relTolerance*math.Abs(az)) {
+			t.Errorf("field at %v: expected (%g, %g), got (%g, %g)", fp, ar, az, gr, gz)
+		}
+	}
+	for _, f := range graded.WindingForces() {
+		if f.Layer == 1 && f.Current != 0 {
+			t.Fatalf("expected the spare layer to carry no current, got %g A", f.Current)
+		}
+	}
+	if !approxEqual(graded.CurrentDensity()*2, alone.CurrentDensity(), relTolerance*alone.CurrentDensity()) {
+		t.Errorf("expected the spare layer to halve the mean current density, got %g and %g", graded.CurrentDensity(), alone.CurrentDensity())
+	}
+
+	// Setting the current density of a layer takes it out of series.
+	powered.SetCurrentDensity(0)
+	if powered.Series || powered.Current != 0 {
+		t.Errorf("expected an unpowered layer, got %+v", powered)
+	}
+}
+
+func TestNewGradedSolenoidValidation(t *testing.T) {
+	valid := Layer{Radius: 0.1, Thickness: 0.01, Length: 0.2, Nturns: 40, Series: true}
+	tt := []struct {
+		name  string
+		layer func(l Layer) Layer
+	}{
+		{name: "no_turns", layer: func(l Layer) Layer { l.Nturns = 0; return l }},
+		{name: "no_length", layer: func(l Layer) Layer { l.Length = 0; return l }},
+		{name: "negative_thickness", layer: func(l Layer) Layer { l.Thickness = -0.01; return l }},
+		{name: "thicker_than_radius", layer: func(l Layer) Layer { l.Thickness = 0.3; return l }},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewGradedSolenoid(100, 0, valid, tc.layer(valid)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+	if _, err := NewGradedSolenoid(100, 0); err == nil {
+		t.Errorf("expected an error for a solenoid with no layers")
+	}
+}
+
+func TestThinLayer(t *testing.T) {
+	relTolerance := 1e-9
+	// A layer with no thickness is a current sheet, just like a uniform winding with no radial build.
+	sheet := NewSolenoid(0.1, 0.1, 0.2, 100, 0, 40, 1)
+	graded := newTestGradedSolenoid(t, 100, 0, Layer{Radius: 0.1, Length: 0.2, Nturns: 40, Series: true})
+
+	for _, z := range []float64{0, 0.05, 0.3} {
+		expected, actual := sheet.OnAxisField(z), graded.OnAxisField(z)
+		if math.IsNaN(actual) || !approxEqual(actual/expected, 1, relTolerance) {
+			t.Errorf("on-axis field at z = %g: expected %g, got %g", z, expected, actual)
+		}
+	}
+	for _, fp := range []FieldPoint{NewPolarPoint(0.05, 0, 0.03), NewPolarPoint(0.2, 0, -0.1)} {
+		// The sheet agrees with the loops away from the winding.
+		_, _, loops := graded.CalculateFieldAtPointUsing(fp, MethodLoopSum)
+		_, _, block := graded.CalculateFieldAtPointUsing(fp, MethodCurrentBlock)
+		if !approxEqual(block/loops, 1, 1e-3) {
+			t.Errorf("field at %v: expected %g, got %g", fp, loops, block)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package golenoid
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+// This file contains the analytic calculation of the gradient of the magnetic field.
+// The derivatives of the loop field are found by differentiating the elliptic integral expressions with the chain rule,
+// using dK/dm = (E - (1-m)K) / (2m(1-m)) and dE/dm = (E - K) / (2m), where m = k^2.
+
+// Tensor is a 3x3 tensor in cartesian components. The gradient of the field is held as T[i][j] = ∂Bi/∂xj.
+type Tensor [3][3]float64
+
+// Divergence returns the trace of the gradient tensor, ∇·B.
+func (t Tensor) Divergence() float64 {
+	return t[0][0] + t[1][1] + t[2][2]
+}
+
+// Curl returns the curl of the field whose gradient tensor is t, ∇×B.
+func (t Tensor) Curl() Vec3 {
+	return Vec3{
+		t[2][1] - t[1][2],
+		t[0][2] - t[2][0],
+		t[1][0] - t[0][1],
+	}
+}
+
+// Add returns the sum t + u.
+func (t Tensor) Add(u Tensor) Tensor {
+	for i := range t {
+		for j := range t[i] {
+			t[i][j] += u[i][j]
+		}
+	}
+	return t
+}
+
+// rotate returns the tensor in the frame obtained by applying r, R t Rᵀ.
+func (t Tensor) rotate(r Rotation) Tensor {
+	var rotated Tensor
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				for l := 0; l < 3; l++ {
+					rotated[i][j] += r[i][k] * t[k][l] * r[j][l]
+				}
+			}
+		}
+	}
+	return rotated
+}
+
+// ellipticDerivatives returns the derivatives of the complete elliptic integrals K(m) and E(m) with respect to m.
+func ellipticDerivatives(m float64) (dK, dE float64) {
+	if m < 1e-4 {
+		// The closed forms cancel to O(m) close to the axis, so their series are used there.
+		return math.Pi / 2 * (0.25 + 9*m/32 + 75*m*m/256), math.Pi / 2 * (-0.25 - 3*m/32 - 15*m*m/256)
+	}
+	K := mathext.CompleteK(m)
+	E := mathext.CompleteE(m)
+	return (E - (1-m)*K) / (2 * m * (1 - m)), (E - K) / (2 * m)
+}
+
+// CalculateFieldGradientFromLoopPolar calculates the derivatives of the polar components of the magnetic field with
+// respect to r and z at point (r,phi,z) induced from a current loop.
+//
+// The coordinate (0, 0, 0) lies at the very centre of the current loop. The derivatives with respect to phi vanish.
+// The input variables are:
+//   - current: the current in the loop in amperes
+//   - a: the radius of the current loop in metres
+//   - r: the r coordinate of the point in metres
+//   - z: the z coordinate of the point in metres
+func CalculateFieldGradientFromLoopPolar(current, a, r, z float64) (dBrdr, dBrdz, dBzdr, dBzdz float64) {
+	C := calculateC(current)
+
+	if r == 0 {
+		// On the magnetic axis Br vanishes for every z and Bz is extremal in r, so only dBz/dz is found from the
+		// on-axis field, mu0 I a^2 / (2 (a^2 + z^2)^(3/2)). dBr/dr then follows from ∇·B = 0.
+		dBzdz = -3 * math.Pi * C * a * a * z / (2 * math.Pow(a*a+z*z, 2.5))
+		return -dBzdz / 2, 0, 0, dBzdz
+	}
+
+	alphaSq := a*a + r*r + z*z - 2*a*r
+	betaSq := a*a + r*r + z*z + 2*a*r
+	beta := math.Sqrt(betaSq)
+	m := 4 * a * r / betaSq
+
+	K := mathext.CompleteK(m)
+	E := mathext.CompleteE(m)
+	dK, dE := ellipticDerivatives(m)
+
+	// Derivatives of m, alpha^2 and beta with respect to r and z.
+	dmdr := 4 * a * (a*a + z*z - r*r) / (betaSq * betaSq)
+	dmdz := -8 * a * r * z / (betaSq * betaSq)
+	dAlphaSqdr, dAlphaSqdz := 2*(r-a), 2*z
+	dBetadr, dBetadz := (r+a)/beta, z/beta
+
+	// Bz = C Nz / D and Br = C z Nr / (r D), with D = 2 alpha^2 beta.
+	Nz := (a*a-r*r-z*z)*E + alphaSq*K
+	Nr := (a*a+r*r+z*z)*E - alphaSq*K
+	D := 2 * alphaSq * beta
+
+	dNzdr := -2*r*E + (a*a-r*r-z*z)*dE*dmdr + dAlphaSqdr*K + alphaSq*dK*dmdr
+	dNzdz := -2*z*E + (a*a-r*r-z*z)*dE*dmdz + dAlphaSqdz*K + alphaSq*dK*dmdz
+	dNrdr := 2*r*E + (a*a+r*r+z*z)*dE*dmdr - dAlphaSqdr*K - alphaSq*dK*dmdr
+	dNrdz := 2*z*E + (a*a+r*r+z*z)*dE*dmdz - dAlphaSqdz*K - alphaSq*dK*dmdz
+	dDdr := 2*dAlphaSqdr*beta + 2*alphaSq*dBetadr
+	dDdz := 2*dAlphaSqdz*beta + 2*alphaSq*dBetadz
+
+	dBzdr = C * (dNzdr*D - Nz*dDdr) / (D * D)
+	dBzdz = C * (dNzdz*D - Nz*dDdz) / (D * D)
+	dBrdr = C * z * (dNrdr/(r*D) - Nr/(r*r*D) - Nr*dDdr/(r*D*D))
+	dBrdz = C / r * (Nr/D + z*dNrdz/D - z*Nr*dDdz/(D*D))
+	return
+}
+
+// cartesianGradient converts the derivatives of the polar components of an axisymmetric field at (x, y) into the
+// cartesian gradient tensor. Br is needed as well, as the direction of r changes with x and y.
+func cartesianGradient(x, y, Br, dBrdr, dBrdz, dBzdr, dBzdz float64) Tensor {
+	r := math.Hypot(x, y)
+	// On the axis Br/r tends to dBr/dr and the direction is arbitrary.
+	BrOverR, cos, sin := dBrdr, 1., 0.
+	if r != 0 {
+		BrOverR, cos, sin = Br/r, x/r, y/r
+	}
+
+	return Tensor{
+		{dBrdr*cos*cos + BrOverR*sin*sin, (dBrdr - BrOverR) * cos * sin, dBrdz * cos},
+		{(dBrdr - BrOverR) * cos * sin, dBrdr*sin*sin + BrOverR*cos*cos, dBrdz * sin},
+		{dBzdr * cos, dBzdr * sin, dBzdz},
+	}
+}
+
+// CalculateFieldGradientAtPoint calculates the gradient tensor ∂Bi/∂xj of the field of the solenoid at fp,
+// by summing the analytic gradient of every loop.
+//
+// The tensor is always in global cartesian components, whatever the type of fp. If the solenoid has a Placement,
+// the gradient is calculated in the frame of the coil and rotated back into the global frame.
+func (s *Solenoid) CalculateFieldGradientAtPoint(fp FieldPoint) Tensor {
+	x, y, z := fp.GetCartesianCoordinates()
+	if s.Placement == nil {
+		return s.calculateLocalFieldGradient(x, y, z)
+	}
+	local := s.Placement.ToLocal(Vec3{x, y, z})
+	return s.calculateLocalFieldGradient(local[0], local[1], local[2]).rotate(s.Placement.Rotation)
+}
+
+// calculateLocalFieldGradient calculates the gradient tensor at (x, y, z) in the frame of the coil.
+func (s *Solenoid) calculateLocalFieldGradient(x, y, z float64) Tensor {
+	r := math.Hypot(x, y)
+	var Br, dBrdr, dBrdz, dBzdr, dBzdz float64
+	s.forEachLoop(func(a, zLoop, current float64) {
+		br, _, _ := CalculateFieldFromLoopPolar(current, a, r, z-zLoop)
+		drr, drz, dzr, dzz := CalculateFieldGradientFromLoopPolar(current, a, r, z-zLoop)
+		Br += br
+		dBrdr += drr
+		dBrdz += drz
+		dBzdr += dzr
+		dBzdz += dzz
+	})
+	return cartesianGradient(x, y, Br, dBrdr, dBrdz, dBzdr, dBzdz)
+}
+
+// CalculateFieldGradientAtPoint calculates the gradient tensor ∂Bi/∂xj of the field of the loop at fp.
+//
+// The tensor is always in global cartesian components, whatever the type of fp.
+func (l *Loop) CalculateFieldGradientAtPoint(fp FieldPoint) Tensor {
+	x, y, z := fp.GetCartesianCoordinates()
+	var rotation *Rotation
+	if l.Placement != nil {
+		local := l.Placement.ToLocal(Vec3{x, y, z})
+		x, y, z = local[0], local[1], local[2]
+		rotation = &l.Placement.Rotation
+	}
+
+	r := math.Hypot(x, y)
+	Br, _, _ := CalculateFieldFromLoopPolar(l.Current, l.Radius, r, z-l.CentrePos)
+	dBrdr, dBrdz, dBzdr, dBzdz := CalculateFieldGradientFromLoopPolar(l.Current, l.Radius, r, z-l.CentrePos)
+	t := cartesianGradient(x, y, Br, dBrdr, dBrdz, dBzdr, dBzdz)
+	if rotation != nil {
+		t = t.rotate(*rotation)
+	}
+	return t
+}
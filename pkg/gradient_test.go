@@ -0,0 +1,92 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+// numericalGradient calculates the gradient tensor of the field of src at (x, y, z) with central differences.
+func numericalGradient(src FieldSource, x, y, z, h float64) Tensor {
+	var t Tensor
+	for j := 0; j < 3; j++ {
+		plus, minus := Vec3{x, y, z}, Vec3{x, y, z}
+		plus[j] += h
+		minus[j] -= h
+		bxp, byp, bzp := calculateFieldSeq(src, NewCartesianPoint(plus[0], plus[1], plus[2]))
+		bxm, bym, bzm := calculateFieldSeq(src, NewCartesianPoint(minus[0], minus[1], minus[2]))
+		t[0][j] = (bxp - bxm) / (2 * h)
+		t[1][j] = (byp - bym) / (2 * h)
+		t[2][j] = (bzp - bzm) / (2 * h)
+	}
+	return t
+}
+
+// checkGradient compares two gradient tensors relative to the largest element of expected.
+func checkGradient(t *testing.T, expected, actual Tensor, relTolerance float64) {
+	t.Helper()
+	var scale float64
+	for i := range expected {
+		for j := range expected[i] {
+			scale = math.Max(scale, math.Abs(expected[i][j]))
+		}
+	}
+	if scale == 0 {
+		// Compare absolutely when the gradient vanishes.
+		scale = 1
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if !approxEqual(actual[i][j]/scale, expected[i][j]/scale, relTolerance) {
+				t.Errorf("expected %v, got %v", expected, actual)
+				return
+			}
+		}
+	}
+	if div := actual.Divergence(); !approxEqual(div/scale, 0, 1e-9) {
+		t.Errorf("expected no divergence, got %g", div)
+	}
+	if curl := actual.Curl(); curl.Norm()/scale > 1e-9 {
+		t.Errorf("expected no curl, got %v", curl)
+	}
+}
+
+func TestCalculateFieldGradientFromLoop(t *testing.T) {
+	loop := NewLoop(0.1, 100, 0.02)
+
+	// Br cancels badly close to the axis, which limits the accuracy of the numerical gradient there.
+	tt := []struct {
+		name         string
+		x, y, z      float64
+		relTolerance float64
+	}{
+		{name: "on_axis", x: 0, y: 0, z: 0.07, relTolerance: 1e-5},
+		{name: "centre", x: 0, y: 0, z: 0.02, relTolerance: 1e-12},
+		{name: "near_axis", x: 1e-4, y: 2e-4, z: 0.05, relTolerance: 1e-7},
+		{name: "inside", x: 0.03, y: -0.04, z: 0, relTolerance: 1e-7},
+		{name: "near_wire", x: 0.09, y: 0.01, z: 0.03, relTolerance: 1e-7},
+		{name: "outside", x: -0.2, y: 0.1, z: -0.1, relTolerance: 1e-7},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := numericalGradient(loop, tc.x, tc.y, tc.z, 1e-6)
+			checkGradient(t, expected, loop.CalculateFieldGradientAtPoint(NewCartesianPoint(tc.x, tc.y, tc.z)), tc.relTolerance)
+		})
+	}
+}
+
+func TestSolenoidFieldGradient(t *testing.T) {
+	relTolerance := 1e-6
+	s := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+	s.Placement = NewPlacement(Vec3{0.01, -0.02, 0.05}, NewRotationFromEuler(0.1, -0.2, 0.3))
+
+	for _, fp := range []FieldPoint{
+		NewCartesianPoint(0.02, 0.03, 0.1),
+		NewPolarPoint(0.05, 1.2, -0.2),
+		NewCartesianPoint(0.2, 0.1, 0.3),
+	} {
+		x, y, z := fp.GetCartesianCoordinates()
+		expected := numericalGradient(s, x, y, z, 1e-6)
+		checkGradient(t, expected, s.CalculateFieldGradientAtPoint(fp), relTolerance)
+	}
+}
@@ -0,0 +1,197 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// Axis describes exactly N evenly spaced values starting at Min.
+//
+// If Endpoint is true the last value is Max, otherwise Max is excluded, which is useful for periodic
+// axes such as phi from 0 to 2π.
+type Axis struct {
+	Min      float64
+	Max      float64
+	N        int
+	Endpoint bool
+}
+
+// NewAxis creates a new Axis of n values between min and max.
+func NewAxis(min, max float64, n int, endpoint bool) Axis {
+	return Axis{
+		Min:      min,
+		Max:      max,
+		N:        n,
+		Endpoint: endpoint,
+	}
+}
+
+// Step returns the spacing between consecutive values of the axis. It is 0 if the axis has fewer than 2 values.
+func (a Axis) Step() float64 {
+	if a.Endpoint {
+		if a.N < 2 {
+			return 0
+		}
+		return (a.Max - a.Min) / float64(a.N-1)
+	}
+	if a.N < 1 {
+		return 0
+	}
+	return (a.Max - a.Min) / float64(a.N)
+}
+
+// Value returns the i-th value of the axis.
+//
+// Values are calculated from their index rather than accumulated, so the last value is exactly Max when Endpoint is true.
+func (a Axis) Value(i int) float64 {
+	if a.Endpoint && i == a.N-1 && a.N > 1 {
+		return a.Max
+	}
+	return a.Min + float64(i)*a.Step()
+}
+
+// Values returns all N values of the axis.
+func (a Axis) Values() []float64 {
+	values := make([]float64, a.N)
+	for i := range values {
+		values[i] = a.Value(i)
+	}
+	return values
+}
+
+// Grid is a structured grid of points with three axes, where the last axis varies fastest.
+type Grid interface {
+	// Axes returns the three axes of the grid.
+	Axes() [3]Axis
+	// Dims returns the number of points along each axis.
+	Dims() [3]int
+	// At returns the point with index i along the first axis, j along the second and k along the third.
+	At(i, j, k int) FieldPoint
+	// Slice returns every point whose index along axis is index, in grid order.
+	Slice(axis, index int) []FieldPoint
+	// Field returns the Field holding every point of the grid, which shares its points with the grid.
+	Field() *Field
+}
+
+var (
+	_ Grid = (*CartesianGrid)(nil)
+	_ Grid = (*CylindricalGrid)(nil)
+	_ Grid = (*SphericalGrid)(nil)
+)
+
+// structuredGrid implements the index aware access shared by all grids.
+type structuredGrid struct {
+	axes  [3]Axis
+	field *Field
+}
+
+// newStructuredGrid creates the points of a grid with the given axes using newPoint.
+func newStructuredGrid(axes [3]Axis, newPoint func(u, v, w float64) FieldPoint) structuredGrid {
+	for i, a := range axes {
+		if a.N < 1 {
+			panic(fmt.Sprintf("axis %d of a grid must have at least 1 value, got %d", i, a.N))
+		}
+	}
+
+	us, vs, ws := axes[0].Values(), axes[1].Values(), axes[2].Values()
+	field := &Field{
+		Points: make([]FieldPoint, 0, len(us)*len(vs)*len(ws)),
+		Dims:   [3]int{len(us), len(vs), len(ws)},
+	}
+	for _, u := range us {
+		for _, v := range vs {
+			for _, w := range ws {
+				field.Points = append(field.Points, newPoint(u, v, w))
+			}
+		}
+	}
+	return structuredGrid{axes: axes, field: field}
+}
+
+// Axes returns the three axes of the grid.
+func (g *structuredGrid) Axes() [3]Axis {
+	return g.axes
+}
+
+// Dims returns the number of points along each axis.
+func (g *structuredGrid) Dims() [3]int {
+	return g.field.Dims
+}
+
+// Index returns the position in Field().Points of the point with indices (i, j, k).
+// It panics if any index is out of range.
+func (g *structuredGrid) Index(i, j, k int) int {
+	d := g.field.Dims
+	if i < 0 || i >= d[0] || j < 0 || j >= d[1] || k < 0 || k >= d[2] {
+		panic(fmt.Sprintf("grid index (%d, %d, %d) out of range for dims %v", i, j, k, d))
+	}
+	return (i*d[1]+j)*d[2] + k
+}
+
+// At returns the point with index i along the first axis, j along the second and k along the third.
+func (g *structuredGrid) At(i, j, k int) FieldPoint {
+	return g.field.Points[g.Index(i, j, k)]
+}
+
+// Slice returns every point whose index along axis is index, in grid order.
+func (g *structuredGrid) Slice(axis, index int) []FieldPoint {
+	if axis < 0 || axis > 2 {
+		panic(fmt.Sprintf("grid axis %d out of range", axis))
+	}
+	d := g.field.Dims
+	points := make([]FieldPoint, 0, d[0]*d[1]*d[2]/d[axis])
+	for i := 0; i < d[0]; i++ {
+		for j := 0; j < d[1]; j++ {
+			for k := 0; k < d[2]; k++ {
+				if [3]int{i, j, k}[axis] == index {
+					points = append(points, g.At(i, j, k))
+				}
+			}
+		}
+	}
+	return points
+}
+
+// Field returns the Field holding every point of the grid, which shares its points with the grid.
+func (g *structuredGrid) Field() *Field {
+	return g.field
+}
+
+// CartesianGrid is a structured grid of CartesianPoints with axes (x, y, z).
+type CartesianGrid struct {
+	structuredGrid
+}
+
+// NewCartesianGrid creates a new CartesianGrid from the x, y and z axes.
+func NewCartesianGrid(x, y, z Axis) *CartesianGrid {
+	return &CartesianGrid{newStructuredGrid([3]Axis{x, y, z}, func(x, y, z float64) FieldPoint {
+		return NewCartesianPoint(x, y, z)
+	})}
+}
+
+// CylindricalGrid is a structured grid of PolarPoints with axes (r, phi, z).
+type CylindricalGrid struct {
+	structuredGrid
+}
+
+// NewCylindricalGrid creates a new CylindricalGrid from the r, phi and z axes.
+func NewCylindricalGrid(r, phi, z Axis) *CylindricalGrid {
+	return &CylindricalGrid{newStructuredGrid([3]Axis{r, phi, z}, func(r, phi, z float64) FieldPoint {
+		return NewPolarPoint(r, phi, z)
+	})}
+}
+
+// SphericalGrid is a structured grid with axes (rho, theta, phi), where rho is the distance from the origin,
+// theta the angle from the positive z-axis and phi the azimuthal angle.
+//
+// The points are PolarPoints, so fields are available in cylindrical or cartesian components.
+type SphericalGrid struct {
+	structuredGrid
+}
+
+// NewSphericalGrid creates a new SphericalGrid from the rho, theta and phi axes.
+func NewSphericalGrid(rho, theta, phi Axis) *SphericalGrid {
+	return &SphericalGrid{newStructuredGrid([3]Axis{rho, theta, phi}, func(rho, theta, phi float64) FieldPoint {
+		return NewPolarPoint(rho*math.Sin(theta), phi, rho*math.Cos(theta))
+	})}
+}
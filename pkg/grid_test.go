@@ -0,0 +1,118 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAxisValues(t *testing.T) {
+	tt := []struct {
+		name     string
+		axis     Axis
+		expected []float64
+	}{
+		{name: "endpoint", axis: NewAxis(0, 1, 5, true), expected: []float64{0, 0.25, 0.5, 0.75, 1}},
+		{name: "no_endpoint", axis: NewAxis(0, 1, 4, false), expected: []float64{0, 0.25, 0.5, 0.75}},
+		{name: "single_value", axis: NewAxis(2, 3, 1, true), expected: []float64{2}},
+		{name: "awkward_step", axis: NewAxis(-1, 1, 2000, true)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.axis.Values()
+			if len(actual) != tc.axis.N {
+				t.Fatalf("expected %d values, got %d", tc.axis.N, len(actual))
+			}
+			if tc.axis.Endpoint && tc.axis.N > 1 && actual[len(actual)-1] != tc.axis.Max {
+				t.Errorf("expected the last value to be %g, got %g", tc.axis.Max, actual[len(actual)-1])
+			}
+			for i := range tc.expected {
+				if !approxEqual(actual[i], tc.expected[i], 1e-15) {
+					t.Errorf("expected %v, got %v", tc.expected, actual)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGridAccess(t *testing.T) {
+	grid := NewCartesianGrid(NewAxis(0, 1, 2, true), NewAxis(0, 2, 3, true), NewAxis(0, 3, 4, true))
+
+	if dims := grid.Dims(); dims != [3]int{2, 3, 4} {
+		t.Fatalf("expected dims [2 3 4], got %v", dims)
+	}
+	if n := len(grid.Field().Points); n != 24 {
+		t.Fatalf("expected 24 points, got %d", n)
+	}
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 4; k++ {
+				x, y, z := grid.At(i, j, k).GetCartesianCoordinates()
+				if x != float64(i) || y != float64(j) || z != float64(k) {
+					t.Errorf("At(%d, %d, %d): got (%g, %g, %g)", i, j, k, x, y, z)
+				}
+			}
+		}
+	}
+
+	slice := grid.Slice(1, 2)
+	if len(slice) != 8 {
+		t.Fatalf("expected 8 points in the slice, got %d", len(slice))
+	}
+	for _, p := range slice {
+		if _, y, _ := p.GetCartesianCoordinates(); y != 2 {
+			t.Errorf("expected every point in the slice to have y = 2, got %v", p)
+		}
+	}
+}
+
+func TestSphericalGrid(t *testing.T) {
+	tolerance := 1e-15
+	grid := NewSphericalGrid(NewAxis(1, 2, 2, true), NewAxis(0, math.Pi, 3, true), NewAxis(0, 2*math.Pi, 4, false))
+
+	x, y, z := grid.At(1, 1, 1).GetCartesianCoordinates()
+	if !approxEqual(x, 0, tolerance) || !approxEqual(y, 2, tolerance) || !approxEqual(z, 0, tolerance) {
+		t.Errorf("expected (0, 2, 0), got (%g, %g, %g)", x, y, z)
+	}
+	x, y, z = grid.At(0, 2, 3).GetCartesianCoordinates()
+	if !approxEqual(x, 0, tolerance) || !approxEqual(y, 0, tolerance) || !approxEqual(z, -1, tolerance) {
+		t.Errorf("expected (0, 0, -1), got (%g, %g, %g)", x, y, z)
+	}
+}
+
+func TestCalculateFieldWithWorkersOrder(t *testing.T) {
+	loop := NewLoop(0.2, 10, 0)
+	field := CalculateFieldWithWorkers(loop, 0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 2, 7, 4)
+
+	if field.Dims != [3]int{3, 2, 7} || len(field.Points) != 42 {
+		t.Fatalf("expected 42 points with dims [3 2 7], got %d with dims %v", len(field.Points), field.Dims)
+	}
+	expected := NewFieldGridPolar(0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 2, 7)
+	CalculateFullField(loop, expected)
+	checkFieldsEqual(t, expected, field, CoordinatesPolar)
+}
+
+func TestNewFieldGridPolarFullTurn(t *testing.T) {
+	tolerance := 1e-15
+	tt := []struct {
+		name     string
+		phiMax   float64
+		expected []float64
+	}{
+		{name: "half turn", phiMax: math.Pi, expected: []float64{0, math.Pi / 3, 2 * math.Pi / 3, math.Pi}},
+		{name: "full turn", phiMax: 2 * math.Pi, expected: []float64{0, math.Pi / 2, math.Pi, 3 * math.Pi / 2}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			field := NewFieldGridPolar(0.1, 0.1, 0, tc.phiMax, 0, 0, 1, len(tc.expected), 1)
+			for i, fp := range field.Points {
+				_, phi, _ := fp.GetPolarCoordinates()
+				if !approxEqual(phi, tc.expected[i], tolerance) {
+					t.Errorf("point %d: expected phi = %g, got %g", i, tc.expected[i], phi)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains the zonal (axisymmetric spherical harmonic) expansion of the field of coaxial coils.
+//
+// Inside a sphere about an origin on the axis that contains no conductor, the field is given in spherical coordinates
+// (ρ, θ) about the origin by
+//
+//	Bz = Σ b_n ρ^n P_n(cos θ)
+//	Br = -Σ b_n ρ^n sin θ P'_n(cos θ) / (n+1)
+//
+// where b_n is the n-th derivative of Bz along the axis over n!, i.e. Bz = Σ b_n z^n on the axis.
+// For a loop of radius a whose conductor lies a distance R from the origin at an angle α from the axis,
+// b_n = μ0 I a² P'_{n+1}(cos α) / (2 R^{n+3}).
+
+// coaxialLoop is a current loop on the z-axis of the global frame.
+type coaxialLoop struct {
+	radius  float64
+	z       float64
+	current float64
+}
+
+// coaxialLoops returns every loop of src in the global frame. Every source must be a coil whose magnetic axis is
+// the z-axis, otherwise an error is returned.
+func coaxialLoops(src FieldSource) ([]coaxialLoop, error) {
+	// placed returns a function that moves a loop of a coil with the placement into the global frame.
+	placed := func(p *Placement) func(a, z, current float64) coaxialLoop {
+		origin, direction := p.axis()
+		// A coil flipped by its placement carries its current the other way around the z-axis.
+		sign := math.Copysign(1, direction[2])
+		return func(a, z, current float64) coaxialLoop {
+			return coaxialLoop{radius: a, z: origin[2] + sign*z, current: sign * current}
+		}
+	}
+
+	switch s := src.(type) {
+	case *Solenoid:
+		if !s.Axisymmetric() {
+			return nil, fmt.Errorf("%w with the z-axis: %s", ErrNotCoaxial, s.Description())
+		}
+		global := placed(s.Placement)
+		var loops []coaxialLoop
+		s.forEachLoop(func(a, z, current float64) {
+			loops = append(loops, global(a, z, current))
+		})
+		return loops, nil
+	case *Loop:
+		if !s.Axisymmetric() {
+			return nil, fmt.Errorf("%w with the z-axis: %s", ErrNotCoaxial, s.Description())
+		}
+		return []coaxialLoop{placed(s.Placement)(s.Radius, s.CentrePos, s.Current)}, nil
+	case *solenoidUsing:
+		return coaxialLoops(s.solenoid)
+	case *MagnetSystem:
+		var loops []coaxialLoop
+		for _, src := range s.Sources {
+			l, err := coaxialLoops(src)
+			if err != nil {
+				return nil, err
+			}
+			loops = append(loops, l...)
+		}
+		return loops, nil
+	default:
+		return nil, fmt.Errorf("cannot expand the field of %T", src)
+	}
+}
+
+// legendre returns P_n(x) and P'_n(x) for n from 0 to order.
+func legendre(x float64, order int) (p, dp []float64) {
+	p = make([]float64, order+1)
+	dp = make([]float64, order+1)
+	p[0] = 1
+	if order > 0 {
+		p[1] = x
+		dp[1] = 1
+	}
+	for n := 1; n < order; n++ {
+		p[n+1] = (float64(2*n+1)*x*p[n] - float64(n)*p[n-1]) / float64(n+1)
+		dp[n+1] = dp[n-1] + float64(2*n+1)*p[n]
+	}
+	return p, dp
+}
+
+// HarmonicExpansion is the zonal harmonic expansion of the field of coaxial coils about an origin on the z-axis.
+//
+// It implements FieldSource, and is far cheaper to evaluate than the loop sum inside the sphere of convergence.
+// Outside the sphere of convergence the field is NaN.
+type HarmonicExpansion struct {
+	Origin       float64   // Position of the origin of the expansion on the z-axis in metres
+	Coefficients []float64 // Coefficients b_n in teslas per metre^n, from n = 0
+	Radius       float64   // Radius of convergence in metres, the distance from the origin to the nearest conductor
+
+	description string
+}
+
+var (
+	_ FieldSource        = (*HarmonicExpansion)(nil)
+	_ AxisymmetricSource = (*HarmonicExpansion)(nil)
+)
+
+// NewHarmonicExpansion calculates the zonal harmonic coefficients of src about the point origin on the z-axis up to
+// and including the given order.
+//
+// src must be a Solenoid, Loop or MagnetSystem of them whose magnetic axes are the z-axis. The coefficients are
+// found analytically from every loop, so they are exact up to rounding whatever the order.
+func NewHarmonicExpansion(src FieldSource, origin float64, order int) (*HarmonicExpansion, error) {
+	if order < 0 {
+		return nil, fmt.Errorf("order must not be negative, got %d", order)
+	}
+	loops, err := coaxialLoops(src)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &HarmonicExpansion{
+		Origin:       origin,
+		Coefficients: make([]float64, order+1),
+		Radius:       math.Inf(1),
+		description:  fmt.Sprintf("harmonic expansion to order %d about z = %g m of %s", order, origin, src.Description()),
+	}
+	for _, l := range loops {
+		d := l.z - origin
+		R := math.Hypot(l.radius, d)
+		h.Radius = math.Min(h.Radius, R)
+
+		_, dp := legendre(d/R, order+1)
+		scale := mu0 * l.current * l.radius * l.radius / (2 * R * R * R)
+		for n := range h.Coefficients {
+			h.Coefficients[n] += scale * dp[n+1]
+			scale /= R
+		}
+	}
+	return h, nil
+}
+
+// Order returns the highest order of the expansion.
+func (h *HarmonicExpansion) Order() int {
+	return len(h.Coefficients) - 1
+}
+
+// PPM returns the n-th coefficient normalised to the uniform field in parts per million at the reference radius,
+// b_n radius^n / b_0 * 1e6. This is how the purity of MRI magnets is usually specified, e.g. Z2 is PPM(2, radius).
+func (h *HarmonicExpansion) PPM(n int, radius float64) float64 {
+	return h.Coefficients[n] * math.Pow(radius, float64(n)) / h.Coefficients[0] * 1e6
+}
+
+// Contains reports whether (x, y, z) lies strictly inside the sphere of convergence.
+func (h *HarmonicExpansion) Contains(x, y, z float64) bool {
+	return math.Sqrt(x*x+y*y+(z-h.Origin)*(z-h.Origin)) < h.Radius
+}
+
+// polarField evaluates (Br, Bz) at (r, z) from the expansion, or NaN outside the sphere of convergence.
+func (h *HarmonicExpansion) polarField(r, z float64) (Br, Bz float64) {
+	z -= h.Origin
+	rho := math.Hypot(r, z)
+	if rho >= h.Radius {
+		return math.NaN(), math.NaN()
+	}
+	if rho == 0 {
+		return 0, h.Coefficients[0]
+	}
+
+	p, dp := legendre(z/rho, h.Order())
+	// rho^n, with sin θ ρ^n = r ρ^(n-1) kept as a separate power so that the axis needs no special case.
+	power, rPower := 1., r/rho
+	for n, b := range h.Coefficients {
+		Bz += b * power * p[n]
+		Br -= b * rPower * dp[n] / float64(n+1)
+		power *= rho
+		rPower *= rho
+	}
+	return
+}
+
+// CalculateFieldAtPoint evaluates the field at the point fp from the expansion.
+func (h *HarmonicExpansion) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	r, phi, z := fp.GetPolarCoordinates()
+	Br, Bz := h.polarField(r, z)
+	switch fp.(type) {
+	case *PolarPoint:
+		return Br, 0, Bz
+	default:
+		bx, by, bz := PolarToCartesianField(Br, 0, Bz, phi)
+		return cartesianFieldAtPoint(fp, bx, by, bz)
+	}
+}
+
+// BoundingBox returns the corners of the box that contains the sphere of convergence.
+func (h *HarmonicExpansion) BoundingBox() (min, max Vec3) {
+	return Vec3{-h.Radius, -h.Radius, h.Origin - h.Radius}, Vec3{h.Radius, h.Radius, h.Origin + h.Radius}
+}
+
+// Description returns a human readable description of the expansion and the source it was calculated from.
+func (h *HarmonicExpansion) Description() string {
+	return h.description
+}
+
+// Axisymmetric always reports true, as the expansion is about the z-axis.
+func (h *HarmonicExpansion) Axisymmetric() bool {
+	return true
+}
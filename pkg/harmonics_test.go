@@ -0,0 +1,106 @@
+package golenoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestLegendre(t *testing.T) {
+	tolerance := 1e-15
+	x := 0.3
+	p, dp := legendre(x, 4)
+
+	expectedP := []float64{1, x, (3*x*x - 1) / 2, (5*x*x*x - 3*x) / 2, (35*x*x*x*x - 30*x*x + 3) / 8}
+	expectedDP := []float64{0, 1, 3 * x, (15*x*x - 3) / 2, (140*x*x*x - 60*x) / 8}
+	for n := range expectedP {
+		if !approxEqual(p[n], expectedP[n], tolerance) || !approxEqual(dp[n], expectedDP[n], tolerance) {
+			t.Errorf("n = %d: expected (%g, %g), got (%g, %g)", n, expectedP[n], expectedDP[n], p[n], dp[n])
+		}
+	}
+}
+
+func TestHarmonicExpansion(t *testing.T) {
+	relTolerance := 1e-10
+	s := NewSolenoid(0.1, 0.12, 0.3, 100, 0.02, 30, 2)
+
+	h, err := NewHarmonicExpansion(s, 0.05, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The nearest conductor is the turn of the inner layer 5 mm from the origin.
+	if expected := math.Hypot(0.105, 0.005); !approxEqual(h.Radius, expected, 1e-12) {
+		t.Errorf("expected a radius of convergence of %g, got %g", expected, h.Radius)
+	}
+
+	tt := []struct {
+		name  string
+		point FieldPoint
+	}{
+		{name: "origin", point: NewPolarPoint(0, 0, 0.05)},
+		{name: "on_axis", point: NewPolarPoint(0, 0, 0.1)},
+		{name: "off_axis", point: NewPolarPoint(0.04, 0.3, 0.03)},
+		{name: "cartesian", point: NewCartesianPoint(0.02, -0.03, 0.09)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ei, ej, ek := s.CalculateFieldAtPointUsing(tc.point, MethodLoopSum)
+			ai, aj, ak := h.CalculateFieldAtPoint(tc.point)
+			scale := math.Sqrt(ei*ei + ej*ej + ek*ek)
+			for i, pair := range [][2]float64{{ei, ai}, {ej, aj}, {ek, ak}} {
+				if !approxEqual(pair[0]/scale, pair[1]/scale, relTolerance) {
+					t.Errorf("component %d: expected %g, got %g", i, pair[0], pair[1])
+				}
+			}
+		})
+	}
+
+	if Br, _, _ := h.CalculateFieldAtPoint(NewPolarPoint(0.2, 0, 0.05)); !math.IsNaN(Br) {
+		t.Errorf("expected NaN outside the sphere of convergence, got %g", Br)
+	}
+}
+
+func TestHarmonicExpansionSymmetry(t *testing.T) {
+	tolerance := 1e-9
+	s := NewSolenoid(0.1, 0.12, 0.3, 100, 0.5, 30, 2)
+	s.Placement = NewPlacement(Vec3{0, 0, -0.5}, IdentityRotation())
+
+	// A coil symmetric about the origin has no odd harmonics.
+	h, err := NewHarmonicExpansion(s, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 1; n <= h.Order(); n += 2 {
+		if ppm := h.PPM(n, 0.05); !approxEqual(ppm, 0, tolerance) {
+			t.Errorf("expected no Z%d, got %g ppm", n, ppm)
+		}
+	}
+	if ppm := h.PPM(2, 0.05); ppm >= 0 {
+		t.Errorf("expected a short solenoid to have a negative Z2, got %g ppm", ppm)
+	}
+
+	// Flipping an off-centre coil about the origin mirrors it in z and reverses its current,
+	// so the even coefficients change sign and the odd ones do not.
+	s.CentrePos = 0.6
+	h, _ = NewHarmonicExpansion(s, 0, 10)
+	s.Placement = NewPlacement(Vec3{0, 0, 0.5}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, math.Pi))
+	flipped, err := NewHarmonicExpansion(NewMagnetSystem(s), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := range h.Coefficients {
+		expected := h.Coefficients[n]
+		if n%2 == 0 {
+			expected = -expected
+		}
+		if !approxEqual(flipped.Coefficients[n]/expected, 1, tolerance) {
+			t.Errorf("b%d: expected %g, got %g", n, expected, flipped.Coefficients[n])
+		}
+	}
+
+	s.Placement = NewPlacement(Vec3{0.01, 0, 0}, IdentityRotation())
+	if _, err := NewHarmonicExpansion(s, 0, 10); !errors.Is(err, ErrNotCoaxial) {
+		t.Errorf("expected ErrNotCoaxial, got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// This file contains a model of the winding of a solenoid as the wire is actually wound, a continuous helix along
+// every layer joined by transitions between layers, rather than a stack of closed circular loops.
+// The pitch of the helix gives every layer a net axial current, which runs in alternate directions in alternate
+// layers, and the ends of the helix are not closed, so the field has a small transverse component that the loop
+// model misses.
+
+// defaultSegmentsPerTurn is the number of segments each turn is split into if none is given. The integrand is smooth
+// and nearly periodic, so the midpoint rule converges very quickly away from the wire.
+const defaultSegmentsPerTurn = 64
+
+// currentElement is a short element of wire of length and direction dl at position carrying current.
+type currentElement struct {
+	position Vec3
+	dl       Vec3
+	current  float64
+}
+
+// currentSegment is a straight wire from a to b carrying current.
+type currentSegment struct {
+	a, b    Vec3
+	current float64
+}
+
+// HelicalWinding is a FieldSource that integrates the Biot–Savart law along the helical path of the wire of a
+// Solenoid.
+//
+// The wire of every layer starts at phi = 0 at one end of the layer and advances by one pitch per turn, with
+// alternate layers wound in opposite directions along the axis so that the wire runs back and forth, as in a layer
+// wound coil. Consecutive layers that carry the same current are joined by a straight transition from the end of one
+// to the start of the next. If LeadLength is positive, straight radial leads at phi = 0 bring the current in to the
+// start of the first layer from LeadLength outside it and take it out from the end of the last layer. The return path
+// of the leads to the supply is not included.
+type HelicalWinding struct {
+	Solenoid        *Solenoid // Solenoid whose winding is followed
+	SegmentsPerTurn int       // Number of segments each turn is split into
+	LeadLength      float64   // Length of the radial leads in metres, or 0 for no leads
+
+	mu   sync.Mutex
+	path *helicalPath
+}
+
+// helicalPath is the path of the wire of a HelicalWinding in the frame of the coil, and the winding it follows.
+type helicalPath struct {
+	centre          float64
+	layers          []Layer
+	segmentsPerTurn int
+	leadLength      float64
+
+	elements    []currentElement
+	transitions []currentSegment
+	leads       []currentSegment
+}
+
+// NewHelicalWinding creates the helical winding of the solenoid, splitting every turn into segmentsPerTurn segments,
+// or defaultSegmentsPerTurn if it is not positive.
+//
+// The path of the wire follows the solenoid as it is when the field is calculated, so later changes to its winding,
+// Current and Placement are all seen.
+func NewHelicalWinding(s *Solenoid, segmentsPerTurn int) *HelicalWinding {
+	if segmentsPerTurn <= 0 {
+		segmentsPerTurn = defaultSegmentsPerTurn
+	}
+	return &HelicalWinding{Solenoid: s, SegmentsPerTurn: segmentsPerTurn}
+}
+
+// currentPath returns the path of the wire, rebuilding it if the winding has changed since it was last built.
+func (h *HelicalWinding) currentPath() *helicalPath {
+	layers := h.Solenoid.windingLayers()
+	segmentsPerTurn := h.SegmentsPerTurn
+	if segmentsPerTurn <= 0 {
+		segmentsPerTurn = defaultSegmentsPerTurn
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if p := h.path; p != nil && p.centre == h.Solenoid.CentrePos && p.segmentsPerTurn == segmentsPerTurn &&
+		p.leadLength == h.LeadLength && equalLayers(p.layers, layers) {
+		return p
+	}
+	h.path = newHelicalPath(h.Solenoid.CentrePos, layers, segmentsPerTurn, h.LeadLength)
+	return h.path
+}
+
+// equalLayers reports whether a and b describe the same layers.
+func equalLayers(a, b []Layer) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newHelicalPath builds the path of the wire through the layers of a coil centred at centre.
+func newHelicalPath(centre float64, layers []Layer, segmentsPerTurn int, leadLength float64) *helicalPath {
+	p := &helicalPath{centre: centre, layers: layers, segmentsPerTurn: segmentsPerTurn, leadLength: leadLength}
+
+	dphi := 2 * math.Pi / float64(segmentsPerTurn)
+	var previousEnd Vec3
+	var previousCurrent float64
+	for i, l := range layers {
+		// Even layers are wound towards positive z and odd layers back towards negative z.
+		direction := 1.
+		if i%2 == 1 {
+			direction = -1
+		}
+		zStart := centre + l.Offset - direction*l.Length/2
+		start := Vec3{l.Radius, 0, zStart}
+		if i == 0 && leadLength > 0 {
+			p.leads = append(p.leads, currentSegment{a: Vec3{l.Radius + leadLength, 0, zStart}, b: start, current: l.Current})
+		}
+		if i > 0 && previousCurrent == l.Current {
+			p.transitions = append(p.transitions, currentSegment{a: previousEnd, b: start, current: l.Current})
+		}
+
+		rise := direction * l.pitch() / (2 * math.Pi)
+		for k := 0; k < l.Nturns*segmentsPerTurn; k++ {
+			phi := (float64(k) + 0.5) * dphi
+			sin, cos := math.Sincos(phi)
+			p.elements = append(p.elements, currentElement{
+				position: Vec3{l.Radius * cos, l.Radius * sin, zStart + rise*phi},
+				dl:       Vec3{-l.Radius * sin * dphi, l.Radius * cos * dphi, rise * dphi},
+				current:  l.Current,
+			})
+		}
+
+		previousEnd = Vec3{l.Radius, 0, zStart + direction*l.Length}
+		previousCurrent = l.Current
+		if i == len(layers)-1 && leadLength > 0 {
+			out := Vec3{l.Radius + leadLength, 0, previousEnd[2]}
+			p.leads = append(p.leads, currentSegment{a: previousEnd, b: out, current: l.Current})
+		}
+	}
+	return p
+}
+
+// CalculateFieldAtPoint calculates the magnetic field at the point fp induced by the helical winding.
+//
+// If the solenoid has a Placement, fp is transformed into the frame of the coil before the
+// calculation and the field is rotated back into the global frame.
+func (h *HelicalWinding) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	if h.Solenoid.Placement != nil {
+		return h.Solenoid.Placement.transformField(fp, h.calculateLocalFieldAtPoint)
+	}
+	return h.calculateLocalFieldAtPoint(fp)
+}
+
+// calculateLocalFieldAtPoint calculates the field at fp in the frame of the coil.
+func (h *HelicalWinding) calculateLocalFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	x, y, z := fp.GetCartesianCoordinates()
+	p := Vec3{x, y, z}
+
+	path := h.currentPath()
+	var B Vec3
+	for _, e := range path.elements {
+		r := p.Sub(e.position)
+		d := r.Norm()
+		if d == 0 {
+			continue
+		}
+		B = B.Add(e.dl.Cross(r).Scale(mu0 / (4 * math.Pi) * e.current / (d * d * d)))
+	}
+	for _, s := range path.transitions {
+		B = B.Add(segmentField(s, p))
+	}
+	for _, s := range path.leads {
+		B = B.Add(segmentField(s, p))
+	}
+	return cartesianFieldAtPoint(fp, B[0], B[1], B[2])
+}
+
+// segmentField calculates the field at p of a straight wire segment, using the closed form of the Biot–Savart law
+//
+//	B = μ0 I / 4π (|r1| + |r2|) / (|r1| |r2| (|r1| |r2| + r1·r2)) r1 × r2
+//
+// where r1 and r2 point from the ends of the segment to p. The field vanishes on the line of the segment.
+func segmentField(s currentSegment, p Vec3) Vec3 {
+	r1, r2 := p.Sub(s.a), p.Sub(s.b)
+	n1, n2 := r1.Norm(), r2.Norm()
+	denominator := n1 * n2 * (n1*n2 + r1.Dot(r2))
+	cross := r1.Cross(r2)
+	if denominator == 0 || cross.Norm() == 0 {
+		return Vec3{}
+	}
+	return cross.Scale(mu0 / (4 * math.Pi) * s.current * (n1 + n2) / denominator)
+}
+
+// Deviation returns the field of the helical winding minus the field of the closed loops of the solenoid summed
+// by MethodLoopSum at fp, in the coordinate system of fp. This is the error made by modelling the winding as loops.
+func (h *HelicalWinding) Deviation(fp FieldPoint) (dBi, dBj, dBk float64) {
+	Bi, Bj, Bk := h.CalculateFieldAtPoint(fp)
+	Li, Lj, Lk := h.Solenoid.CalculateFieldAtPointUsing(fp, MethodLoopSum)
+	return Bi - Li, Bj - Lj, Bk - Lk
+}
+
+// BoundingBox returns the corners of an axis aligned box in the global frame that contains the windings.
+func (h *HelicalWinding) BoundingBox() (min, max Vec3) {
+	return h.Solenoid.BoundingBox()
+}
+
+// Description returns a human readable description of the winding.
+func (h *HelicalWinding) Description() string {
+	d := fmt.Sprintf("helical winding of %s with %d segments per turn", h.Solenoid.Description(), h.SegmentsPerTurn)
+	if h.LeadLength > 0 {
+		d += fmt.Sprintf(" and %g m leads", h.LeadLength)
+	}
+	return d
+}
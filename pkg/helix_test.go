@@ -0,0 +1,137 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentField(t *testing.T) {
+	// A long straight wire along z gives the field of an infinite wire, μ0 I / 2πd around it.
+	s := currentSegment{a: Vec3{0, 0, -1e3}, b: Vec3{0, 0, 1e3}, current: 10}
+	B := segmentField(s, Vec3{0.1, 0, 0})
+	if expected := mu0 * 10 / (2 * math.Pi * 0.1); !approxEqual(B[1], expected, 1e-12) || B[0] != 0 || B[2] != 0 {
+		t.Errorf("expected (0, %g, 0), got %v", expected, B)
+	}
+	if B := segmentField(s, Vec3{0, 0, 5}); B != (Vec3{}) {
+		t.Errorf("expected no field on the line of the segment, got %v", B)
+	}
+}
+
+func TestHelicalWinding(t *testing.T) {
+	single := NewSolenoid(0.05, 0.052, 1, 100, 0, 500, 1)
+	helix := NewHelicalWinding(single, 0)
+	if helix.SegmentsPerTurn != defaultSegmentsPerTurn {
+		t.Errorf("expected %d segments per turn, got %d", defaultSegmentsPerTurn, helix.SegmentsPerTurn)
+	}
+
+	// Away from the wire the axial field is that of the loops, up to the end effects of the helix.
+	central := mu0 * 100 * 500
+	for _, fp := range []FieldPoint{NewPolarPoint(0, 0, 0), NewPolarPoint(0.03, 1, 0.2), NewPolarPoint(0.1, 2, 0.45)} {
+		_, _, Bz := helix.CalculateFieldAtPoint(fp)
+		_, _, expected := single.CalculateFieldAtPointUsing(fp, MethodLoopSum)
+		if !approxEqual(Bz, expected, 1e-3*central) {
+			t.Errorf("Bz at %v: expected %g, got %g", fp, expected, Bz)
+		}
+	}
+
+	// Outside a single layer the net axial current of 100 A gives on average the azimuthal field of a line current of
+	// the same length, which the loops miss entirely. The pitch also tilts every turn, which adds a transverse dipole
+	// that averages out around the axis.
+	r := 0.2
+	var mean float64
+	for k := 0; k < 16; k++ {
+		_, Bphi, _ := helix.CalculateFieldAtPoint(NewPolarPoint(r, (float64(k)+0.5)*math.Pi/8, 0))
+		mean += Bphi / 16
+	}
+	if expected := mu0 * 100 / (4 * math.Pi * r) * 2 * 0.5 / math.Hypot(0.5, r); !approxEqual(mean/expected, 1, 2e-3) {
+		t.Errorf("Bphi outside a single layer: expected %g on average, got %g", expected, mean)
+	}
+	_, Bphi, _ := helix.CalculateFieldAtPoint(NewPolarPoint(r, 0.3, 0))
+	_, dBphi, _ := helix.Deviation(NewPolarPoint(r, 0.3, 0))
+	if !approxEqual(dBphi, Bphi, 1e-15) {
+		t.Errorf("expected the deviation to be the azimuthal field %g, got %g", Bphi, dBphi)
+	}
+
+	// With two layers the axial currents run in opposite directions and mostly cancel outside.
+	double := NewHelicalWinding(NewSolenoid(0.05, 0.054, 1, 100, 0, 500, 2), 32)
+	if transitions := double.currentPath().transitions; len(transitions) != 1 {
+		t.Fatalf("expected 1 transition between layers, got %d", len(transitions))
+	}
+	if _, cancelled, _ := double.CalculateFieldAtPoint(NewPolarPoint(r, 0.3, 0)); math.Abs(cancelled) > 0.1*math.Abs(Bphi) {
+		t.Errorf("expected the azimuthal fields of two layers to cancel, got %g against %g for one", cancelled, Bphi)
+	}
+
+	// The open ends of the helix give a transverse field on the axis that is small but not zero.
+	Bx, By, Bz := helix.CalculateFieldAtPoint(NewCartesianPoint(0, 0, 0.45))
+	if transverse := math.Hypot(Bx, By); transverse == 0 || transverse > 1e-2*Bz {
+		t.Errorf("expected a small transverse field on the axis, got %g against Bz = %g", transverse, Bz)
+	}
+}
+
+func TestHelicalWindingPlacement(t *testing.T) {
+	s := NewSolenoid(0.05, 0.054, 0.2, 50, 0, 40, 2)
+	local := NewHelicalWinding(s, 32)
+	fp := NewCartesianPoint(0.01, 0.02, 0.03)
+	bx, by, bz := local.CalculateFieldAtPoint(fp)
+
+	rotation := NewRotationFromAxisAngle(Vec3{1, 1, 0}, 0.7)
+	placed := NewHelicalWinding(&Solenoid{Rinner: 0.05, Router: 0.054, Length: 0.2, Current: 50, Nturns: 40, Nlayers: 2,
+		Placement: &Placement{Translation: Vec3{0.1, 0, -0.2}, Rotation: rotation}}, 32)
+	p := placed.Solenoid.Placement.ToGlobal(Vec3{0.01, 0.02, 0.03})
+	Bx, By, Bz := placed.CalculateFieldAtPoint(NewCartesianPoint(p[0], p[1], p[2]))
+
+	expected := rotation.Apply(Vec3{bx, by, bz})
+	for i, actual := range []float64{Bx, By, Bz} {
+		if !approxEqual(actual, expected[i], 1e-15) {
+			t.Errorf("component %d: expected %g, got %g", i, expected[i], actual)
+		}
+	}
+}
+
+func TestHelicalWindingFollowsSolenoid(t *testing.T) {
+	relTolerance := 1e-12
+	s := NewSolenoid(0.05, 0.054, 0.2, 50, 0, 40, 2)
+	helix := NewHelicalWinding(s, 32)
+	fp := NewCartesianPoint(0.01, 0.02, 0.03)
+	_, _, before := helix.CalculateFieldAtPoint(fp)
+
+	// Changes to the solenoid after the winding is created are seen, so the deviation is against the same winding.
+	s.Current = 100
+	_, _, after := helix.CalculateFieldAtPoint(fp)
+	if !approxEqual(after/before, 2, relTolerance) {
+		t.Errorf("expected doubling the current to double the field, got %g and %g", before, after)
+	}
+	s.Nturns = 20
+	fresh := NewHelicalWinding(NewSolenoid(0.05, 0.054, 0.2, 100, 0, 20, 2), 32)
+	dBx, dBy, dBz := helix.Deviation(fp)
+	ex, ey, ez := fresh.Deviation(fp)
+	for i, pair := range [][2]float64{{ex, dBx}, {ey, dBy}, {ez, dBz}} {
+		if !approxEqual(pair[1], pair[0], relTolerance*math.Abs(after)) {
+			t.Errorf("component %d: expected a deviation of %g, got %g", i, pair[0], pair[1])
+		}
+	}
+}
+
+func TestHelicalWindingLeads(t *testing.T) {
+	s := NewSolenoid(0.05, 0.054, 0.2, 50, 0, 40, 2)
+	helix := NewHelicalWinding(s, 32)
+	fp := NewCartesianPoint(0.2, 0.05, 0.15)
+	Bx, By, Bz := helix.CalculateFieldAtPoint(fp)
+
+	// The lead in runs radially in to the start of the inner layer at its lower end, and the lead out radially out
+	// from the end of the outer layer, which is wound back down to the lower end.
+	helix.LeadLength = 0.5
+	in := currentSegment{a: Vec3{0.551, 0, -0.1}, b: Vec3{0.051, 0, -0.1}, current: 50}
+	out := currentSegment{a: Vec3{0.053, 0, -0.1}, b: Vec3{0.553, 0, -0.1}, current: 50}
+	expected := Vec3{Bx, By, Bz}.Add(segmentField(in, Vec3{0.2, 0.05, 0.15})).Add(segmentField(out, Vec3{0.2, 0.05, 0.15}))
+
+	lx, ly, lz := helix.CalculateFieldAtPoint(fp)
+	for i, actual := range []float64{lx, ly, lz} {
+		if !approxEqual(actual, expected[i], 1e-15) {
+			t.Errorf("component %d: expected %g, got %g", i, expected[i], actual)
+		}
+	}
+	if leads := helix.currentPath().leads; len(leads) != 2 {
+		t.Errorf("expected 2 leads, got %d", len(leads))
+	}
+}
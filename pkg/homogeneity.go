@@ -0,0 +1,86 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains the analysis of the homogeneity of the field over a diameter of spherical volume (DSV),
+// the usual figure of merit of MRI magnets.
+
+// FieldExtremum is the position and magnitude of an extreme of the field.
+type FieldExtremum struct {
+	Position Vec3    // Position in the global frame in metres
+	B        float64 // Magnitude of the field in teslas
+}
+
+// HomogeneityReport describes the homogeneity of the magnitude of the field over the surface of a sphere.
+type HomogeneityReport struct {
+	Centre Vec3    // Centre of the sphere in metres
+	DSV    float64 // Diameter of the sphere in metres
+
+	Mean          float64       // Mean of the magnitude of the field over the surface of the sphere in teslas
+	Min           FieldExtremum // Smallest sampled magnitude of the field
+	Max           FieldExtremum // Largest sampled magnitude of the field
+	PeakToPeakPPM float64       // (Max - Min) / Mean in parts per million
+
+	// Field holds the sampled points, with Dims (1, nTheta, nPhi), so it can be written out.
+	Field *Field
+}
+
+// AnalyseHomogeneity samples the field of src on the surface of the sphere of diameter dsv about centre, and reports
+// the peak-to-peak homogeneity of the magnitude of the field, its extremes and its mean.
+//
+// The sphere is sampled at nTheta polar angles from pole to pole, both included, and nPhi azimuthal angles.
+// Bz is harmonic inside a current free sphere, so its extremes lie on the surface and sampling the surface is enough.
+// The mean is weighted by the area each sample represents. For sources that are axisymmetric about the z-axis with
+// the centre on the z-axis, nPhi = 1 is enough and the field is calculated once per polar angle in any case.
+func AnalyseHomogeneity(src FieldSource, centre Vec3, dsv float64, nTheta, nPhi int) (*HomogeneityReport, error) {
+	if dsv <= 0 {
+		return nil, fmt.Errorf("dsv must be positive, got %g", dsv)
+	}
+	if nTheta < 2 || nPhi < 1 {
+		return nil, fmt.Errorf("need at least 2 polar and 1 azimuthal angles, got %d and %d", nTheta, nPhi)
+	}
+
+	theta := NewAxis(0, math.Pi, nTheta, true)
+	phi := NewAxis(0, 2*math.Pi, nPhi, false)
+	radius := dsv / 2
+
+	field := &Field{Dims: [3]int{1, nTheta, nPhi}}
+	for _, t := range theta.Values() {
+		for _, p := range phi.Values() {
+			sinT, cosT := math.Sincos(t)
+			sinP, cosP := math.Sincos(p)
+			field.Points = append(field.Points,
+				NewCartesianPoint(centre[0]+radius*sinT*cosP, centre[1]+radius*sinT*sinP, centre[2]+radius*cosT))
+		}
+	}
+	CalculateFullField(src, field)
+
+	report := &HomogeneityReport{Centre: centre, DSV: dsv, Field: field}
+	thetaWeights := trapezoidWeights(theta)
+	var totalWeight float64
+	for i, fp := range field.Points {
+		Bx, By, Bz := fp.GetCartesianField()
+		B := math.Sqrt(Bx*Bx + By*By + Bz*Bz)
+		x, y, z := fp.GetCartesianCoordinates()
+		position := Vec3{x, y, z}
+
+		if i == 0 || B < report.Min.B {
+			report.Min = FieldExtremum{Position: position, B: B}
+		}
+		if i == 0 || B > report.Max.B {
+			report.Max = FieldExtremum{Position: position, B: B}
+		}
+
+		// The area of the surface element is proportional to sin θ dθ dφ, and every φ has the same weight.
+		t := i / nPhi
+		weight := thetaWeights[t] * math.Sin(theta.Value(t))
+		report.Mean += weight * B
+		totalWeight += weight
+	}
+	report.Mean /= totalWeight
+	report.PeakToPeakPPM = (report.Max.B - report.Min.B) / report.Mean * 1e6
+	return report, nil
+}
@@ -0,0 +1,66 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyseHomogeneity(t *testing.T) {
+	s := NewSolenoid(0.3, 0.32, 0.6, 100, 0, 60, 2)
+	dsv := 0.1
+
+	report, err := AnalyseHomogeneity(s, Vec3{}, dsv, 19, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Field.Points) != 19*8 || !report.Field.IsStructured() {
+		t.Fatalf("expected a structured field of %d points, got %d with dims %v", 19*8, len(report.Field.Points), report.Field.Dims)
+	}
+	if report.Min.B > report.Mean || report.Mean > report.Max.B {
+		t.Errorf("expected the mean %g to lie between %g and %g", report.Mean, report.Min.B, report.Max.B)
+	}
+	if expected := (report.Max.B - report.Min.B) / report.Mean * 1e6; report.PeakToPeakPPM != expected {
+		t.Errorf("expected %g ppm, got %g", expected, report.PeakToPeakPPM)
+	}
+
+	// A short solenoid has a negative Z2, so the field is weakest at the poles and strongest on the equator.
+	if !approxEqual(math.Abs(report.Min.Position[2]), dsv/2, 1e-12) || !approxEqual(report.Max.Position[2], 0, 1e-12) {
+		t.Errorf("expected the minimum at a pole and the maximum on the equator, got %v and %v", report.Min.Position, report.Max.Position)
+	}
+
+	// Z2 dominates, and P2 goes from 1 at the poles to -1/2 on the equator.
+	h, err := NewHarmonicExpansion(s, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 1.5 * math.Abs(h.PPM(2, dsv/2)); !approxEqual(report.PeakToPeakPPM/expected, 1, 0.05) {
+		t.Errorf("expected about %g ppm from Z2, got %g", expected, report.PeakToPeakPPM)
+	}
+
+	// Moving the sphere off the centre of the coil makes the field less homogeneous.
+	shifted, err := AnalyseHomogeneity(s, Vec3{0.01, 0, 0.05}, dsv, 19, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shifted.PeakToPeakPPM <= report.PeakToPeakPPM {
+		t.Errorf("expected an off-centre sphere to be less homogeneous, got %g and %g ppm", shifted.PeakToPeakPPM, report.PeakToPeakPPM)
+	}
+}
+
+func TestAnalyseHomogeneityUniform(t *testing.T) {
+	// The magnitude of the linear field on a sphere about the origin is sqrt((1+2x-y)^2 + 9z^2 + (x+y+z)^2),
+	// whose mean over a small sphere tends to 1.
+	report, err := AnalyseHomogeneity(linearSource{}, Vec3{}, 1e-6, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(report.Mean, 1, 1e-6) || report.PeakToPeakPPM > 10 {
+		t.Errorf("expected a nearly uniform field of 1 T, got %+v", report)
+	}
+
+	for _, args := range [][3]float64{{0, 3, 4}, {0.1, 1, 4}, {0.1, 3, 0}} {
+		if _, err := AnalyseHomogeneity(linearSource{}, Vec3{}, args[0], int(args[1]), int(args[2])); err == nil {
+			t.Errorf("expected an error for dsv %g, nTheta %g and nPhi %g", args[0], args[1], args[2])
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package golenoid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// This file contains the calculation of self and mutual inductances.
+// Every turn is treated as a filamentary loop, so the inductance is the sum of the mutual inductances between every
+// pair of turns, except for the inductance of a turn with itself, which accounts for the cross-section of the conductor.
+// The cross-section of a turn is the share of its layer it occupies, as wide as the pitch of the turns and as high as
+// the layer.
+
+// ErrNotCoaxial is returned when the mutual inductance of two coils whose magnetic axes do not coincide is requested.
+var ErrNotCoaxial = errors.New("golenoid: coils are not coaxial")
+
+// MutualInductanceOfLoops calculates the mutual inductance in henries between two coaxial circular loops.
+//
+// The input variables are:
+//   - a: the radius of the first loop in metres
+//   - b: the radius of the second loop in metres
+//   - d: the distance between the planes of the loops in metres
+//
+// It is the flux through the second loop per ampere in the first, 2πb Aphi.
+// The loops must not coincide, where the mutual inductance of filaments diverges.
+func MutualInductanceOfLoops(a, b, d float64) float64 {
+	return 2 * math.Pi * b * CalculateVectorPotentialFromLoop(1, a, b, d)
+}
+
+// selfInductanceOfTurn calculates the self-inductance of a circular turn of radius a with a rectangular
+// cross-section width by height, using the geometric mean distance of the rectangle from itself.
+func selfInductanceOfTurn(a, width, height float64) float64 {
+	gmd := 0.2235 * (width + height)
+	return mu0 * a * (math.Log(8*a/gmd) - 2)
+}
+
+// SelfInductance calculates the self-inductance of the solenoid in henries.
+//
+// Turns are evenly spaced along every layer, so the mutual inductance of two turns of layers with the same turn count
+// and extent only depends on how many turns apart they are. The sum over every pair of turns of such layers is
+// therefore taken over turn separations, which takes Nlayers² × Nturns evaluations rather than (Nlayers × Nturns)².
+// Every layer of a graded winding is treated as if it was in Series with the rest, whatever its Current.
+func (s *Solenoid) SelfInductance() float64 {
+	return s.turnPairSum(func(a, b Layer) float64 { return 1 })
+}
+
+// turnPairSum sums the self-inductance of every turn and the mutual inductance of every pair of turns of the
+// solenoid, weighting the sum over a pair of layers by weight.
+func (s *Solenoid) turnPairSum(weight func(a, b Layer) float64) float64 {
+	layers := s.windingLayers()
+	var L float64
+	for i, a := range layers {
+		for j := i; j < len(layers); j++ {
+			b := layers[j]
+			layerPair := weight(a, b) * layerPairInductance(a, b, b.turnZ(0, 0)-a.turnZ(0, 0))
+			if i == j {
+				L += layerPair
+			} else {
+				// The pair of layers (j, i) contributes the same as (i, j).
+				L += 2 * layerPair
+			}
+		}
+	}
+	return L
+}
+
+// layerPairInductance sums the mutual inductance of every turn of layer a with every turn of layer b, where the first
+// turn of b is shift further along the axis than the first turn of a and the turns of both run towards positive z.
+// Turns that coincide contribute the self-inductance of a turn, so the sum of a layer with itself is its inductance.
+func layerPairInductance(a, b Layer, shift float64) float64 {
+	if a.Nturns != b.Nturns || a.Length != b.Length {
+		var M float64
+		for k := 0; k < a.Nturns; k++ {
+			for l := 0; l < b.Nturns; l++ {
+				M += turnInductance(a, b, shift+float64(l)*b.pitch()-float64(k)*a.pitch())
+			}
+		}
+		return M
+	}
+
+	// The layers have the same pitch, so there are Nturns - |n| pairs of turns whose indices differ by n.
+	var M float64
+	for n := 1 - a.Nturns; n < a.Nturns; n++ {
+		M += float64(a.Nturns-abs(n)) * turnInductance(a, b, shift+float64(n)*a.pitch())
+	}
+	return M
+}
+
+// turnInductance calculates the mutual inductance of a turn of layer a and a turn of layer b that is d further along
+// the axis. The mutual inductance of filaments diverges where they coincide, so coincident turns are treated as a
+// single turn of the cross-section of a turn of a, with the self-inductance of that turn.
+func turnInductance(a, b Layer, d float64) float64 {
+	if math.Abs(a.Radius-b.Radius) < axisymmetricTolerance && math.Abs(d) < axisymmetricTolerance {
+		return selfInductanceOfTurn(a.Radius, a.pitch(), a.Thickness)
+	}
+	return MutualInductanceOfLoops(a.Radius, b.Radius, d)
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// axis returns a point on the magnetic axis of a coil with the placement and the direction of the axis,
+// both in the global frame. A nil placement has its axis along the z-axis.
+func (p *Placement) axis() (origin, direction Vec3) {
+	if p == nil {
+		return Vec3{}, Vec3{0, 0, 1}
+	}
+	return p.Translation, p.Rotation.Apply(Vec3{0, 0, 1})
+}
+
+// coaxialOffset reports how the frame of a coil with placement q relates to the frame of a coil with placement p,
+// if their magnetic axes coincide. The position z along the axis in the frame of q is offset + sign*z in the frame of p,
+// where sign is -1 if the axes point in opposite directions.
+func coaxialOffset(p, q *Placement) (offset, sign float64, err error) {
+	pOrigin, pDirection := p.axis()
+	qOrigin, qDirection := q.axis()
+
+	delta := qOrigin.Sub(pOrigin)
+	offset = delta.Dot(pDirection)
+	perpendicular := delta.Sub(pDirection.Scale(offset))
+	if pDirection.Cross(qDirection).Norm() > axisymmetricTolerance || perpendicular.Norm() > axisymmetricTolerance {
+		return 0, 0, ErrNotCoaxial
+	}
+	return offset, math.Copysign(1, pDirection.Dot(qDirection)), nil
+}
+
+// MutualInductance calculates the mutual inductance in henries between the solenoid and other.
+//
+// The solenoids must share the same magnetic axis, otherwise ErrNotCoaxial is returned. The mutual inductance is
+// negative if the axes of the solenoids point in opposite directions, as a positive current in one then drives a
+// negative flux through the other.
+func (s *Solenoid) MutualInductance(other *Solenoid) (float64, error) {
+	return s.mutualTurnSum(other, func(current, otherCurrent float64) float64 { return 1 })
+}
+
+// mutualTurnSum sums the mutual inductance of every turn of the solenoid with every turn of other, weighting every
+// pair by weight of the currents of the turns. It returns ErrNotCoaxial if the solenoids are not coaxial.
+//
+// The sum is taken over pairs of layers with layerPairInductance, so layers with the same turn count and extent are
+// summed over turn separations. Turns of the two solenoids that coincide contribute the self-inductance of a turn.
+func (s *Solenoid) mutualTurnSum(other *Solenoid, weight func(current, otherCurrent float64) float64) (float64, error) {
+	offset, sign, err := coaxialOffset(s.Placement, other.Placement)
+	if err != nil {
+		return 0, err
+	}
+
+	var M float64
+	for _, a := range s.windingLayers() {
+		for _, b := range other.windingLayers() {
+			// The turn of b lowest along the axis in the frame of the solenoid, which is its last turn if the axes
+			// point in opposite directions.
+			first := offset + b.turnZ(other.CentrePos, 0)
+			if sign < 0 {
+				first = offset - b.turnZ(other.CentrePos, b.Nturns-1)
+			}
+			M += weight(a.Current, b.Current) * layerPairInductance(a, b, first-a.turnZ(s.CentrePos, 0))
+		}
+	}
+	return sign * M, nil
+}
+
+// InductanceMatrix calculates the inductance matrix of the system in henries, where element (i, j) is the mutual
+// inductance between sources i and j and the diagonal holds their self-inductances.
+//
+// Every source must be a *Solenoid, or a solenoid returned by Solenoid.Using, and every pair must be coaxial,
+// otherwise an error wrapping ErrNotCoaxial is returned.
+func (m *MagnetSystem) InductanceMatrix() (*mat.SymDense, error) {
+	solenoids, err := m.solenoids()
+	if err != nil {
+		return nil, err
+	}
+
+	L := mat.NewSymDense(len(solenoids), nil)
+	for i, s := range solenoids {
+		L.SetSym(i, i, s.SelfInductance())
+		for j := i + 1; j < len(solenoids); j++ {
+			M, err := s.MutualInductance(solenoids[j])
+			if err != nil {
+				return nil, fmt.Errorf("sources %d and %d: %w", i, j, err)
+			}
+			L.SetSym(i, j, M)
+		}
+	}
+	return L, nil
+}
+
+// solenoids returns the solenoid behind every source in the system, or an error if a source is not a solenoid.
+func (m *MagnetSystem) solenoids() ([]*Solenoid, error) {
+	solenoids := make([]*Solenoid, len(m.Sources))
+	for i, src := range m.Sources {
+		switch s := src.(type) {
+		case *Solenoid:
+			solenoids[i] = s
+		case *solenoidUsing:
+			solenoids[i] = s.solenoid
+		default:
+			return nil, fmt.Errorf("source %d: cannot calculate the inductance of %T", i, src)
+		}
+	}
+	return solenoids, nil
+}
@@ -0,0 +1,191 @@
+package golenoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mathext"
+)
+
+func TestMutualInductanceOfLoops(t *testing.T) {
+	relTolerance := 1e-3
+
+	tt := []struct {
+		name     string
+		a, b, d  float64
+		expected float64
+	}{
+		// Far apart the loops are dipoles, M = mu0 pi a^2 b^2 / (2 d^3).
+		{name: "far", a: 0.1, b: 0.05, d: 5, expected: mu0 * math.Pi * 0.01 * 0.0025 / (2 * 125)},
+		// Close together the loops are straight wires, M = mu0 a (ln(8a/d) - 2).
+		{name: "close", a: 1, b: 1, d: 1e-4, expected: mu0 * (math.Log(8/1e-4) - 2)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := MutualInductanceOfLoops(tc.a, tc.b, tc.d)
+			if !approxEqual(actual/tc.expected, 1, relTolerance) {
+				t.Errorf("expected %g, got %g", tc.expected, actual)
+			}
+			if reciprocal := MutualInductanceOfLoops(tc.b, tc.a, -tc.d); !approxEqual(reciprocal/actual, 1, 1e-12) {
+				t.Errorf("expected reciprocity, got %g and %g", actual, reciprocal)
+			}
+		})
+	}
+}
+
+func TestSelfInductance(t *testing.T) {
+	t.Run("pair_sum", func(t *testing.T) {
+		// The grouped sum must match the sum over every pair of turns.
+		s := NewSolenoid(0.1, 0.13, 0.2, 1, 0, 12, 3)
+		var expected float64
+		s.forEachLoop(func(a, z, _ float64) {
+			s.forEachLoop(func(b, zOther, _ float64) {
+				if a == b && z == zOther {
+					expected += selfInductanceOfTurn(a, 0.2/12, 0.01)
+					return
+				}
+				expected += MutualInductanceOfLoops(a, b, zOther-z)
+			})
+		})
+		if actual := s.SelfInductance(); !approxEqual(actual/expected, 1, 1e-12) {
+			t.Errorf("expected %g, got %g", expected, actual)
+		}
+	})
+
+	t.Run("nagaoka", func(t *testing.T) {
+		// A single layer solenoid with many turns is a current sheet with L = mu0 N^2 pi a^2 / l times Nagaoka's coefficient.
+		// The correction for the discrete turns is of order l / (a N), so a short coil is used to keep it small.
+		a, length, n := 0.1, 0.1, 2000
+		s := NewSolenoid(a-1e-5, a+1e-5, length, 1, 0, n, 1)
+
+		ksq := 4 * a * a / (4*a*a + length*length)
+		k, kp := math.Sqrt(ksq), math.Sqrt(1-ksq)
+		K, E := mathext.CompleteK(ksq), mathext.CompleteE(ksq)
+		nagaoka := 4 / (3 * math.Pi * kp) * ((1-ksq)/ksq*(K-E) + E - k)
+
+		expected := mu0 * float64(n*n) * math.Pi * a * a / length * nagaoka
+		if actual := s.SelfInductance(); !approxEqual(actual/expected, 1, 1e-3) {
+			t.Errorf("expected %g, got %g", expected, actual)
+		}
+	})
+}
+
+func TestMutualInductance(t *testing.T) {
+	relTolerance := 1e-12
+	inner := NewSolenoid(0.1, 0.11, 0.3, 1, 0, 30, 2)
+	outer := NewSolenoid(0.15, 0.16, 0.2, 1, 0.1, 20, 1)
+
+	M, err := inner.MutualInductance(outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if M <= 0 {
+		t.Errorf("expected a positive mutual inductance, got %g", M)
+	}
+	if reciprocal, _ := outer.MutualInductance(inner); !approxEqual(reciprocal/M, 1, relTolerance) {
+		t.Errorf("expected reciprocity, got %g and %g", M, reciprocal)
+	}
+
+	// Moving both coils together along the axis changes nothing.
+	inner.Placement = NewPlacement(Vec3{0, 0, 0.5}, IdentityRotation())
+	outer.Placement = NewPlacement(Vec3{0, 0, 0.5}, IdentityRotation())
+	if moved, _ := inner.MutualInductance(outer); !approxEqual(moved/M, 1, relTolerance) {
+		t.Errorf("expected %g after moving, got %g", M, moved)
+	}
+
+	// Flipping the outer coil about its centre reverses its axis.
+	outer.CentrePos = 0
+	outer.Placement = NewPlacement(Vec3{0, 0, 0.6}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, math.Pi))
+	if flipped, _ := inner.MutualInductance(outer); !approxEqual(flipped/M, -1, 1e-9) {
+		t.Errorf("expected %g after flipping, got %g", -M, flipped)
+	}
+
+	outer.Placement = NewPlacement(Vec3{0.01, 0, 0.6}, IdentityRotation())
+	if _, err := inner.MutualInductance(outer); !errors.Is(err, ErrNotCoaxial) {
+		t.Errorf("expected ErrNotCoaxial, got %v", err)
+	}
+}
+
+func TestMutualInductanceTurnSum(t *testing.T) {
+	// Layers with the same turn count and length are summed over turn separations, which must match the sum over
+	// every pair of turns.
+	inner := NewSolenoid(0.1, 0.11, 0.3, 1, 0, 30, 2)
+	outer := NewSolenoid(0.15, 0.17, 0.3, 1, 0.05, 30, 2)
+
+	var expected float64
+	inner.forEachLoop(func(a, z, _ float64) {
+		outer.forEachLoop(func(b, zOther, _ float64) {
+			expected += MutualInductanceOfLoops(a, b, zOther-z)
+		})
+	})
+	M, err := inner.MutualInductance(outer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(M/expected, 1, 1e-12) {
+		t.Errorf("expected %g, got %g", expected, M)
+	}
+
+	// Flipping the outer coil about its centre reverses its axis and leaves its turns where they were.
+	outer.CentrePos = 0
+	outer.Placement = NewPlacement(Vec3{0, 0, 0.05}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, math.Pi))
+	if flipped, _ := inner.MutualInductance(outer); !approxEqual(flipped/expected, -1, 1e-9) {
+		t.Errorf("expected %g after flipping, got %g", -expected, flipped)
+	}
+}
+
+func TestMutualInductanceCoincident(t *testing.T) {
+	// Coincident turns share their flux, so identical coaxial coils have the self-inductance as their mutual
+	// inductance rather than the divergent mutual inductance of coincident filaments.
+	relTolerance := 1e-12
+	forward := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+	reverse := NewSolenoid(0.1, 0.12, 0.3, -100, 0, 20, 2)
+
+	M, err := forward.MutualInductance(reverse)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if L := forward.SelfInductance(); !approxEqual(M/L, 1, relTolerance) {
+		t.Errorf("expected %g, got %g", L, M)
+	}
+
+	energy, err := NewMagnetSystem(forward, reverse).StoredEnergy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scale := forward.StoredEnergy(); !approxEqual(energy/scale, 0, relTolerance) {
+		t.Errorf("expected the stored energy to cancel, got %g", energy)
+	}
+}
+
+func TestInductanceMatrix(t *testing.T) {
+	inner := NewSolenoid(0.1, 0.11, 0.3, 1, 0, 30, 2)
+	outer := NewSolenoid(0.15, 0.16, 0.2, 1, 0.1, 20, 1)
+	system := NewMagnetSystem(inner, outer.Using(MethodCurrentBlock))
+
+	L, err := system.InductanceMatrix()
+	if err != nil {
+		t.Fatal(err)
+	}
+	M, _ := inner.MutualInductance(outer)
+	for _, tc := range []struct {
+		i, j     int
+		expected float64
+	}{
+		{0, 0, inner.SelfInductance()},
+		{1, 1, outer.SelfInductance()},
+		{0, 1, M},
+		{1, 0, M},
+	} {
+		if actual := L.At(tc.i, tc.j); actual != tc.expected {
+			t.Errorf("(%d, %d): expected %g, got %g", tc.i, tc.j, tc.expected, actual)
+		}
+	}
+
+	system.Add(NewLoop(0.1, 1, 0))
+	if _, err := system.InductanceMatrix(); err == nil {
+		t.Errorf("expected an error for a loop")
+	}
+}
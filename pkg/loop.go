@@ -0,0 +1,61 @@
+package golenoid
+
+import "fmt"
+
+// Loop represents a single circular current loop with a negligible cross-section.
+type Loop struct {
+	Radius    float64 // Radius of the loop
+	Current   float64 // Current in the loop (in Amperes)
+	CentrePos float64 // Position of the centre of the loop along the z-axis
+
+	// Placement optionally moves and rotates the loop away from the z-axis.
+	// A nil Placement means the magnetic axis lies along the z-axis.
+	Placement *Placement
+}
+
+// NewLoop creates a new Loop with the given parameters.
+func NewLoop(radius, current, centre float64) *Loop {
+	return &Loop{
+		Radius:    radius,
+		Current:   current,
+		CentrePos: centre,
+	}
+}
+
+// CalculateFieldAtPoint calculates the magnetic field at the point fp induced by the loop.
+func (l *Loop) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	if l.Placement != nil {
+		return l.Placement.transformField(fp, l.calculateLocalFieldAtPoint)
+	}
+	return l.calculateLocalFieldAtPoint(fp)
+}
+
+// calculateLocalFieldAtPoint calculates the field at fp in the frame of the loop.
+func (l *Loop) calculateLocalFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	switch p := fp.(type) {
+	case *CartesianPoint:
+		return CalculateFieldFromLoopCartesian(l.Current, l.Radius, p.X, p.Y, p.Z-l.CentrePos)
+	case *PolarPoint:
+		return CalculateFieldFromLoopPolar(l.Current, l.Radius, p.R, p.Z-l.CentrePos)
+	default:
+		panic(fmt.Sprintf("Unsupported point type: %T", p))
+	}
+}
+
+// BoundingBox returns the corners of an axis aligned box in the global frame that contains the loop.
+func (l *Loop) BoundingBox() (min, max Vec3) {
+	return boundingBox(
+		Vec3{-l.Radius, -l.Radius, l.CentrePos},
+		Vec3{l.Radius, l.Radius, l.CentrePos},
+		l.Placement,
+	)
+}
+
+// Description returns a human readable description of the loop and its parameters.
+func (l *Loop) Description() string {
+	d := fmt.Sprintf("Loop{Radius: %g m, Current: %g A, CentrePos: %g m}", l.Radius, l.Current, l.CentrePos)
+	if l.Placement != nil {
+		d += fmt.Sprintf(" placed at %v with rotation %v", l.Placement.Translation, l.Placement.Rotation)
+	}
+	return d
+}
@@ -0,0 +1,248 @@
+package golenoid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// This file contains the peak field on the conductor of a solenoid and the load-line margin of a superconducting winding.
+
+// ConductorField is the magnitude of the field on one turn of a solenoid.
+type ConductorField struct {
+	Layer   int     // Index of the layer, counting outwards from Rinner or in the order of Layers
+	Turn    int     // Index of the turn in the layer, counting from the negative end of the coil
+	Radius  float64 // Radius in metres of the edge of the turn where the field is evaluated
+	Z       float64 // Position of the turn along the axis in the frame of the coil in metres
+	Current float64 // Current in the turn in amperes
+	B       float64 // Magnitude of the field at the turn in teslas
+	Angle   float64 // Angle between the field and the axis of the coil in radians, from 0 to π/2
+}
+
+// PeakFieldReport holds the field on every turn of a solenoid and where it peaks.
+type PeakFieldReport struct {
+	Turns  []ConductorField // Field on every turn, ordered by layer and then by turn
+	Layers []ConductorField // Turn with the highest field in every layer
+	Peak   ConductorField   // Turn with the highest field in the winding
+}
+
+// PeakField calculates the magnitude of the field on every turn of the solenoid due to its own field and the field
+// of the external sources, and reports where it peaks overall and in every layer.
+//
+// The field is evaluated at the inner and outer edge of every turn, at the Radius of its layer less and plus half its
+// Thickness, and the larger of the two is kept, rather than at its centre as in WindingForces. The field varies
+// across the radial build of the turn, outwards on the inner layers and towards the corners of the winding at its
+// ends, so the centre of the turn would underestimate the peak field on the conductor. The field of the solenoid
+// itself is that of MethodCurrentBlock, which is finite inside the winding and cheap enough to evaluate for every turn.
+func (s *Solenoid) PeakField(external ...FieldSource) *PeakFieldReport {
+	from := NewMagnetSystem(external...)
+	layers := s.windingLayers()
+	report := &PeakFieldReport{Layers: make([]ConductorField, len(layers))}
+	for i, l := range layers {
+		for j := 0; j < l.Nturns; j++ {
+			z := l.turnZ(s.CentrePos, j)
+			var cf ConductorField
+			for k, a := range []float64{l.Radius - l.Thickness/2, l.Radius + l.Thickness/2} {
+				Br, Bz := s.fieldInWinding(from, a, z)
+				if B := math.Hypot(Br, Bz); k == 0 || B > cf.B {
+					cf = ConductorField{Layer: i, Turn: j, Radius: a, Z: z, Current: l.Current,
+						B: B, Angle: math.Atan2(math.Abs(Br), math.Abs(Bz))}
+				}
+			}
+			if j == 0 || cf.B > report.Layers[i].B {
+				report.Layers[i] = cf
+			}
+			if len(report.Turns) == 0 || cf.B > report.Peak.B {
+				report.Peak = cf
+			}
+			report.Turns = append(report.Turns, cf)
+		}
+	}
+	return report
+}
+
+// CriticalCurrentDensity gives the critical current density of a superconductor in amperes per square metre at
+// field b in teslas and temperature t in kelvin. It must decrease with b.
+// It implements CriticalSurface for isotropic conductors.
+type CriticalCurrentDensity func(b, t float64) float64
+
+// ErrNoCriticalCurrent is returned when a load line does not meet the critical surface, e.g. a load line with no field
+// on a critical surface that diverges at zero field.
+var ErrNoCriticalCurrent = errors.New("golenoid: load line does not reach the critical surface")
+
+// LoadLine is the line through the origin in the plane of peak field and current along which a winding moves as its
+// current is ramped, as the field is proportional to the current.
+type LoadLine struct {
+	FieldPerCurrent float64 // Peak field per ampere in teslas per ampere
+	Angle           float64 // Angle of the peak field in radians, as defined by CriticalSurface
+	Area            float64 // Area of the conductor in square metres that carries the current
+}
+
+// Intersect returns the current in amperes at which the load line meets the critical surface cs at temperature t,
+// which is the short-sample limit of the conductor.
+func (l LoadLine) Intersect(cs CriticalSurface, t float64) (float64, error) {
+	if l.Area <= 0 {
+		return 0, fmt.Errorf("area must be positive, got %g m²", l.Area)
+	}
+	// The current is below the critical surface at 0 A, so the intersection is bracketed by 0 and the first power
+	// of 2 above it.
+	below := func(current float64) bool {
+		return cs.Jc(current*l.FieldPerCurrent, l.Angle, t) > current/l.Area
+	}
+	if !below(0) {
+		return 0, fmt.Errorf("conductor is not superconducting at %g K", t)
+	}
+	lo, hi := 0., 1.
+	for below(hi) {
+		lo, hi = hi, 2*hi
+		if hi > 1e15 {
+			return 0, ErrNoCriticalCurrent
+		}
+	}
+	for hi-lo > 1e-12*hi {
+		if mid := (lo + hi) / 2; below(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, nil
+}
+
+// MarginReport describes where a winding operates on its load line.
+type MarginReport struct {
+	Limiting    ConductorField // Turn that reaches the critical surface first, with its field at the operating point
+	Temperature float64        // Operating temperature in kelvin
+
+	OperatingCurrentDensity float64 // Current density in the conductor of the Limiting turn in amperes per square metre
+	CriticalCurrentDensity  float64 // Critical current density at the field of the Limiting turn and Temperature
+
+	// The load line of the Limiting turn meets the critical surface at CriticalCurrent and CriticalField.
+	CriticalCurrent  float64 // Current of the solenoid in amperes at which the winding reaches the critical surface
+	CriticalField    float64 // Field in teslas on the Limiting turn when the winding reaches the critical surface
+	LoadLineFraction float64 // Fraction of the load line used, Current / CriticalCurrent
+	Margin           float64 // Load-line margin, 1 - LoadLineFraction
+}
+
+// LoadLineMargin calculates the load-line margin of the solenoid wound from conductor c, at temperature t with
+// critical current density jc over the area of c. It is LoadLineMarginOn for an isotropic conductor.
+func (s *Solenoid) LoadLineMargin(c Conductor, jc CriticalCurrentDensity, t float64, external ...FieldSource) (*MarginReport, error) {
+	return s.LoadLineMarginOn(c, jc, t, external...)
+}
+
+// LoadLineMarginOn calculates the load-line margin of the solenoid wound from conductor c, at temperature t on the
+// critical surface cs, where the current density of cs is given over the area of c.
+//
+// The field on every turn is found with PeakField and the load line of every turn that carries current is intersected
+// with cs, in the same way as ShortSampleLimit, as the angle of the field or the current of its layer can make a turn
+// other than the one with the highest field reach the critical surface first. The field of the external sources and
+// the current in layers of a graded winding with a Current of their own are scaled with the current of the solenoid
+// along the load line, as if they were powered in series with it.
+func (s *Solenoid) LoadLineMarginOn(c Conductor, cs CriticalSurface, t float64, external ...FieldSource) (*MarginReport, error) {
+	if c.Width <= 0 || c.Height <= 0 {
+		return nil, fmt.Errorf("conductor must have a positive width and height, got %g by %g m", c.Width, c.Height)
+	}
+	if s.Current == 0 {
+		return nil, fmt.Errorf("solenoid carries no current")
+	}
+
+	limiting, _, scale, err := limitingTurn(s.PeakField(external...).Turns, c, cs, t)
+	if err != nil {
+		return nil, err
+	}
+	return &MarginReport{
+		Limiting:                limiting,
+		Temperature:             t,
+		OperatingCurrentDensity: math.Abs(limiting.Current) / c.Area(),
+		CriticalCurrentDensity:  cs.Jc(limiting.B, limiting.Angle, t),
+		CriticalCurrent:         scale * math.Abs(s.Current),
+		CriticalField:           scale * limiting.B,
+		LoadLineFraction:        1 / scale,
+		Margin:                  1 - 1/scale,
+	}, nil
+}
+
+// limitingTurn intersects the load line of every turn that carries current with the critical surface cs at
+// temperature t, for turns wound from conductor c, and returns the turn that reaches cs first with its load line and
+// the factor by which the currents and fields of the turns must be scaled for it to reach cs.
+//
+// Turns whose load line never meets cs, such as a turn in no field on a critical surface that diverges at zero field
+// like NbTi and Nb3Sn, cannot limit the winding and are skipped.
+func limitingTurn(turns []ConductorField, c Conductor, cs CriticalSurface, t float64) (ConductorField, LoadLine, float64, error) {
+	var limiting ConductorField
+	var limitingLine LoadLine
+	scale := math.Inf(1)
+	for _, turn := range turns {
+		current := math.Abs(turn.Current)
+		if current == 0 {
+			continue
+		}
+		line := LoadLine{FieldPerCurrent: turn.B / current, Angle: turn.Angle, Area: c.Area()}
+		critical, err := line.Intersect(cs, t)
+		if errors.Is(err, ErrNoCriticalCurrent) {
+			continue
+		}
+		if err != nil {
+			return ConductorField{}, LoadLine{}, 0, fmt.Errorf("layer %d turn %d: %w", turn.Layer, turn.Turn, err)
+		}
+		if critical/current < scale {
+			limiting, limitingLine, scale = turn, line, critical/current
+		}
+	}
+	if math.IsInf(scale, 1) {
+		return ConductorField{}, LoadLine{}, 0, fmt.Errorf("no turn that carries current reaches the critical surface: %w",
+			ErrNoCriticalCurrent)
+	}
+	return limiting, limitingLine, scale, nil
+}
+
+// ShortSampleReport describes the short-sample limit of a winding, the current at which its conductor reaches the
+// critical surface.
+type ShortSampleReport struct {
+	Limiting    ConductorField // Turn that reaches the critical surface first, with its field at the limit
+	LoadLine    LoadLine       // Load line of the Limiting turn
+	Temperature float64        // Temperature in kelvin
+
+	Current           float64 // Short-sample limit of the Current of the solenoid in amperes
+	OperatingFraction float64 // Fraction of the short-sample limit at the Current of the solenoid
+}
+
+// LoadLine returns the load line of the turn of the solenoid, wound from conductor c, that reaches the critical
+// surface cs first at temperature t, in terms of the current in that turn. It is the LoadLine of ShortSampleLimit.
+func (s *Solenoid) LoadLine(c Conductor, cs CriticalSurface, t float64) (LoadLine, error) {
+	report, err := s.ShortSampleLimit(c, cs, t)
+	if err != nil {
+		return LoadLine{}, err
+	}
+	return report.LoadLine, nil
+}
+
+// ShortSampleLimit finds the short-sample limit of the solenoid wound from conductor c, at temperature t on the
+// critical surface cs, and the fraction of it at which the solenoid operates.
+//
+// Only the field of the solenoid itself is included. The load line of every turn that carries current is intersected
+// with cs rather than only that of the turn with the highest field, as the angle of the field or the current of its
+// layer can make another turn reach the critical surface first. Turns whose load line never meets cs are skipped.
+// The layers of a graded winding with a Current of their own are scaled with the Current of the solenoid, which must
+// then be non-zero.
+func (s *Solenoid) ShortSampleLimit(c Conductor, cs CriticalSurface, t float64) (*ShortSampleReport, error) {
+	if c.Width <= 0 || c.Height <= 0 {
+		return nil, fmt.Errorf("conductor must have a positive width and height, got %g by %g m", c.Width, c.Height)
+	}
+	for i, l := range s.Layers {
+		if s.Current == 0 && !l.Series && l.Current != 0 {
+			return nil, fmt.Errorf("layer %d: cannot scale the current of the layer with a solenoid that carries no current", i)
+		}
+	}
+
+	// At 1 A the scale of the limiting turn is the short-sample limit of the Current of the solenoid.
+	limiting, line, limit, err := limitingTurn(s.withCurrent(1).PeakField().Turns, c, cs, t)
+	if err != nil {
+		return nil, err
+	}
+	report := &ShortSampleReport{Limiting: limiting, LoadLine: line, Temperature: t, Current: limit}
+	report.Limiting.B *= report.Current
+	report.Limiting.Current *= report.Current
+	report.OperatingFraction = math.Abs(s.Current) / report.Current
+	return report, nil
+}
@@ -0,0 +1,246 @@
+package golenoid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPeakField(t *testing.T) {
+	s := NewSolenoid(0.1, 0.12, 0.3, 200, 0, 150, 10)
+
+	report := s.PeakField()
+	if len(report.Turns) != 1500 || len(report.Layers) != 10 {
+		t.Fatalf("expected 1500 turns and 10 layers, got %d and %d", len(report.Turns), len(report.Layers))
+	}
+	// The field on the conductor is highest on the inner layer at the centre of the coil and falls outwards.
+	if report.Peak.Layer != 0 || (report.Peak.Turn != 74 && report.Peak.Turn != 75) {
+		t.Errorf("expected the peak at the centre of the inner layer, got %+v", report.Peak)
+	}
+	for i := 1; i < len(report.Layers); i++ {
+		if report.Layers[i].B >= report.Layers[i-1].B {
+			t.Errorf("expected the peak field to fall outwards, got %g in layer %d and %g in layer %d",
+				report.Layers[i-1].B, i-1, report.Layers[i].B, i)
+		}
+	}
+	for _, cf := range report.Turns {
+		if cf.B > report.Peak.B {
+			t.Errorf("turn %+v exceeds the peak %+v", cf, report.Peak)
+		}
+	}
+
+	// The field is evaluated on the edges of the turns, which see more field than their centres.
+	if report.Peak.Radius != 0.1 {
+		t.Errorf("expected the peak on the inner edge of the winding at 0.1 m, got %g m", report.Peak.Radius)
+	}
+	for i, f := range s.WindingForces() {
+		if centre := math.Hypot(f.Br, f.Bz); centre >= report.Turns[i].B {
+			t.Errorf("layer %d turn %d: expected the edges to exceed the %g T at the centre, got %g T",
+				f.Layer, f.Turn, centre, report.Turns[i].B)
+		}
+	}
+
+	// The external field adds to the field on the conductor.
+	if withBackground := s.PeakField(NewSolenoid(0.5, 0.51, 2, 1000, 0, 200, 1)); withBackground.Peak.B <= report.Peak.B {
+		t.Errorf("expected a background field to raise the peak, got %g and %g", withBackground.Peak.B, report.Peak.B)
+	}
+}
+
+func TestLoadLineMargin(t *testing.T) {
+	relTolerance := 1e-9
+	s := NewSolenoid(0.1, 0.12, 0.3, 200, 0, 150, 10)
+	c := Conductor{Width: 1.5e-3, Height: 1.5e-3}
+
+	// With jc = J0 (1 - B/Bc2), the load line J = lambda Jop, B = lambda Bp meets it at lambda = J0 / (Jop + J0 Bp / Bc2).
+	J0, Bc2 := 3e9, 10.
+	jc := CriticalCurrentDensity(func(b, t float64) float64 { return math.Max(0, J0*(1-b/Bc2)) })
+
+	report, err := s.LoadLineMargin(c, jc, 4.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Bp := s.PeakField().Peak.B
+	Jop := 200 / c.Area()
+	lambda := J0 / (Jop + J0*Bp/Bc2)
+
+	for _, tc := range []struct {
+		name             string
+		expected, actual float64
+	}{
+		{"critical_current", 200 * lambda, report.CriticalCurrent},
+		{"critical_field", Bp * lambda, report.CriticalField},
+		{"margin", 1 - 1/lambda, report.Margin},
+		{"operating_current_density", Jop, report.OperatingCurrentDensity},
+		{"critical_current_density", jc(Bp, 4.2), report.CriticalCurrentDensity},
+	} {
+		if !approxEqual(tc.actual/tc.expected, 1, relTolerance) {
+			t.Errorf("%s: expected %g, got %g", tc.name, tc.expected, tc.actual)
+		}
+	}
+
+	// Above the critical surface the margin is negative.
+	s.Current = 300 * lambda
+	if over, _ := s.LoadLineMargin(c, jc, 4.2); over.Margin >= 0 {
+		t.Errorf("expected a negative margin above the critical surface, got %g", over.Margin)
+	}
+
+	if _, err := s.LoadLineMargin(c, func(b, t float64) float64 { return 0 }, 20); err == nil {
+		t.Errorf("expected an error for a conductor that is not superconducting")
+	}
+}
+
+func TestShortSampleLimit(t *testing.T) {
+	relTolerance := 1e-9
+	s := NewSolenoid(0.1, 0.12, 0.3, 200, 0, 150, 10)
+	c := Conductor{Width: 1.5e-3, Height: 1.5e-3}
+
+	// For an isotropic conductor the turn with the highest field limits the winding, and the limit follows from
+	// J0 (1 - k I/Bc2) = I/A on the load line B = k I.
+	J0, Bc2 := 3e9, 10.
+	jc := CriticalCurrentDensity(func(b, t float64) float64 { return math.Max(0, J0*(1-b/Bc2)) })
+	peak := s.PeakField().Peak
+	line := LoadLine{FieldPerCurrent: peak.B / 200, Angle: peak.Angle, Area: c.Area()}
+	expected := J0 / (1/c.Area() + J0*line.FieldPerCurrent/Bc2)
+	if actual, err := s.LoadLine(c, jc, 4.2); err != nil || !approxEqual(actual.FieldPerCurrent/line.FieldPerCurrent, 1, relTolerance) {
+		t.Errorf("expected the load line of the peak turn %+v, got %+v, %v", line, actual, err)
+	}
+
+	report, err := s.ShortSampleLimit(c, jc, 4.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(report.Current/expected, 1, relTolerance) {
+		t.Errorf("expected a short-sample limit of %g A, got %g A", expected, report.Current)
+	}
+	if !approxEqual(report.OperatingFraction, 200/expected, relTolerance) {
+		t.Errorf("expected an operating fraction of %g, got %g", 200/expected, report.OperatingFraction)
+	}
+	if !approxEqual(report.Limiting.B, line.FieldPerCurrent*report.Current, 1e-9) {
+		t.Errorf("expected a field of %g T at the limit, got %g T", line.FieldPerCurrent*report.Current, report.Limiting.B)
+	}
+	if margin, _ := s.LoadLineMargin(c, jc, 4.2); !approxEqual(margin.CriticalCurrent/report.Current, 1, relTolerance) {
+		t.Errorf("expected the load-line margin to agree with the short-sample limit, got %g A and %g A", margin.CriticalCurrent, report.Current)
+	}
+
+	// A conductor that only carries current in a field parallel to its face is limited by the end turns,
+	// where the field has the largest radial component.
+	anisotropic := CriticalSurface(anisotropicSurface{jc: J0, bPerp: 0.1})
+	report, err = s.ShortSampleLimit(c, anisotropic, 4.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Limiting.Turn != 0 && report.Limiting.Turn != s.Nturns-1 {
+		t.Errorf("expected an end turn to limit an anisotropic conductor, got %+v", report.Limiting)
+	}
+	if _, err := s.LoadLineMarginOn(c, anisotropic, 4.2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Turns whose load line never meets the critical surface cannot limit the winding.
+	unbounded := CriticalSurface(unboundedBelowSurface{jc: jc, theta: math.Pi / 8})
+	report, err = s.ShortSampleLimit(c, unbounded, 4.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Limiting.Angle < math.Pi/8 {
+		t.Errorf("expected a turn in a field at more than π/8 to the axis to limit the winding, got %+v", report.Limiting)
+	}
+	if _, err := s.ShortSampleLimit(c, unboundedBelowSurface{jc: jc, theta: math.Pi}, 4.2); !errors.Is(err, ErrNoCriticalCurrent) {
+		t.Errorf("expected ErrNoCriticalCurrent when no turn reaches the critical surface, got %v", err)
+	}
+}
+
+// unboundedBelowSurface has no critical current density in a field at less than theta to the conductor, and that of
+// jc otherwise.
+type unboundedBelowSurface struct {
+	jc    CriticalCurrentDensity
+	theta float64
+}
+
+func (u unboundedBelowSurface) Jc(b, theta, t float64) float64 {
+	if theta < u.theta {
+		return math.Inf(1)
+	}
+	return u.jc(b, t)
+}
+
+// anisotropicSurface has a critical current density that falls linearly with the field perpendicular to the conductor.
+type anisotropicSurface struct {
+	jc, bPerp float64
+}
+
+func (a anisotropicSurface) Jc(b, theta, t float64) float64 {
+	return math.Max(0, a.jc*(1-b*math.Sin(theta)/a.bPerp))
+}
+
+func TestLoadLineIntersect(t *testing.T) {
+	// A load line with no field meets a field independent critical surface at Jc A.
+	line := LoadLine{FieldPerCurrent: 0, Area: 1e-6}
+	current, err := line.Intersect(CriticalCurrentDensity(func(b, t float64) float64 { return 2e9 }), 4.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(current, 2000, 1e-6) {
+		t.Errorf("expected 2000 A, got %g A", current)
+	}
+
+	if _, err := (LoadLine{FieldPerCurrent: 1e-3, Area: 0}).Intersect(NewNbTiLHC(3e9), 4.2); err == nil {
+		t.Errorf("expected an error for a conductor with no area")
+	}
+	if _, err := (LoadLine{FieldPerCurrent: 1e-3, Area: 1e-6}).Intersect(NewNbTiLHC(3e9), 10); err == nil {
+		t.Errorf("expected an error above the critical temperature")
+	}
+	// The Bottura fit diverges at zero field, so a load line with no field never meets it.
+	if _, err := (LoadLine{FieldPerCurrent: 0, Area: 1e-6}).Intersect(NewNbTiLHC(3e9), 4.2); !errors.Is(err, ErrNoCriticalCurrent) {
+		t.Errorf("expected ErrNoCriticalCurrent for a load line with no field, got %v", err)
+	}
+}
+
+func TestGradedMargin(t *testing.T) {
+	relTolerance := 1e-9
+	c := Conductor{Width: 1.5e-3, Height: 1.5e-3}
+	J0, Bc2 := 3e9, 10.
+	jc := CriticalCurrentDensity(func(b, t float64) float64 { return math.Max(0, J0*(1-b/Bc2)) })
+
+	// The inner layer sees the highest field, but the outer layer carries far more current and limits the winding.
+	inner := Layer{Radius: 0.125, Thickness: 0.0078125, Length: 0.3, Nturns: 150, Series: true}
+	outer := Layer{Radius: 0.2, Thickness: 0.002, Length: 0.3, Nturns: 20, Current: 400}
+	// A spare layer wound just inside the others, whose outer edge touches the inner layer, sees the peak field
+	// of the winding but carries no current.
+	spare := Layer{Radius: 0.1171875, Thickness: 0.0078125, Length: 0.3, Nturns: 150}
+	if peak := newTestGradedSolenoid(t, 100, 0, spare, inner, outer).PeakField().Peak; peak.Current != 0 {
+		t.Fatalf("expected the peak field on the spare layer, got %+v", peak)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		solenoid *Solenoid
+	}{
+		{name: "graded", solenoid: newTestGradedSolenoid(t, 100, 0, inner, outer)},
+		{name: "unpowered", solenoid: newTestGradedSolenoid(t, 100, 0, spare, inner, outer)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			limit, err := tc.solenoid.ShortSampleLimit(c, jc, 4.2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if limit.Limiting.Current == 0 || limit.Limiting.Radius < 0.19 {
+				t.Errorf("expected the outer layer to limit the winding, got %+v", limit.Limiting)
+			}
+
+			margin, err := tc.solenoid.LoadLineMargin(c, jc, 4.2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !approxEqual(margin.CriticalCurrent/limit.Current, 1, relTolerance) {
+				t.Errorf("expected the critical current %g A of the short-sample limit, got %g A", limit.Current, margin.CriticalCurrent)
+			}
+			if !approxEqual(margin.LoadLineFraction/limit.OperatingFraction, 1, relTolerance) {
+				t.Errorf("expected the operating fraction %g, got %g", limit.OperatingFraction, margin.LoadLineFraction)
+			}
+			if margin.Limiting.Layer != limit.Limiting.Layer || margin.Limiting.Turn != limit.Limiting.Turn {
+				t.Errorf("expected the limiting turn %+v, got %+v", limit.Limiting, margin.Limiting)
+			}
+		})
+	}
+}
@@ -122,7 +122,7 @@ func (p *PolarPoint) SetFieldPolar(Br, Bphi, Bz float64) {
 }
 
 func (p *PolarPoint) SetFieldCartesian(Bx, By, Bz float64) {
-	p.Bz, p.Bphi, p.Br = CartesianToPolarField(Bx, By, Bz, p.Phi)
+	p.Br, p.Bphi, p.Bz = CartesianToPolarField(Bx, By, Bz, p.Phi)
 }
 
 func (p *PolarPoint) Magnitude() float64 {
@@ -0,0 +1,59 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains the calculation of the magnetic vector potential of the coils.
+// Only Aphi is non-zero in the frame of a coil, so the vector potential is returned in the components of the point
+// just like the field, i.e. (Ar, Aphi, Az) for a PolarPoint and (Ax, Ay, Az) for a CartesianPoint.
+
+// CalculateVectorPotentialAtPoint calculates the magnetic vector potential at the point fp induced by the solenoid,
+// by summing CalculateVectorPotentialFromLoop over every turn of every layer.
+//
+// If the solenoid has a Placement, fp is transformed into the frame of the coil before the
+// calculation and the potential is rotated back into the global frame.
+func (s *Solenoid) CalculateVectorPotentialAtPoint(fp FieldPoint) (Ai, Aj, Ak float64) {
+	if s.Placement != nil {
+		return s.Placement.transformField(fp, s.calculateLocalVectorPotentialAtPoint)
+	}
+	return s.calculateLocalVectorPotentialAtPoint(fp)
+}
+
+// calculateLocalVectorPotentialAtPoint calculates the vector potential at fp in the frame of the coil.
+func (s *Solenoid) calculateLocalVectorPotentialAtPoint(fp FieldPoint) (Ai, Aj, Ak float64) {
+	r, _, z := fp.GetPolarCoordinates()
+	var Aphi float64
+	s.forEachLoop(func(a, zLoop, current float64) {
+		Aphi += CalculateVectorPotentialFromLoop(current, a, r, z-zLoop)
+	})
+	return vectorPotentialComponents(fp, Aphi)
+}
+
+// CalculateVectorPotentialAtPoint calculates the magnetic vector potential at the point fp induced by the loop.
+func (l *Loop) CalculateVectorPotentialAtPoint(fp FieldPoint) (Ai, Aj, Ak float64) {
+	if l.Placement != nil {
+		return l.Placement.transformField(fp, l.calculateLocalVectorPotentialAtPoint)
+	}
+	return l.calculateLocalVectorPotentialAtPoint(fp)
+}
+
+// calculateLocalVectorPotentialAtPoint calculates the vector potential at fp in the frame of the loop.
+func (l *Loop) calculateLocalVectorPotentialAtPoint(fp FieldPoint) (Ai, Aj, Ak float64) {
+	r, _, z := fp.GetPolarCoordinates()
+	return vectorPotentialComponents(fp, CalculateVectorPotentialFromLoop(l.Current, l.Radius, r, z-l.CentrePos))
+}
+
+// vectorPotentialComponents returns a purely azimuthal vector potential in the components of fp.
+func vectorPotentialComponents(fp FieldPoint, Aphi float64) (Ai, Aj, Ak float64) {
+	switch p := fp.(type) {
+	case *CartesianPoint:
+		_, phi, _ := p.GetPolarCoordinates()
+		return -Aphi * math.Sin(phi), Aphi * math.Cos(phi), 0
+	case *PolarPoint:
+		return 0, Aphi, 0
+	default:
+		panic(fmt.Sprintf("Unsupported point type: %T", p))
+	}
+}
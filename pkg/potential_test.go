@@ -0,0 +1,88 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVectorPotentialFromLoop(t *testing.T) {
+	// B = curl A, so Br = -dAphi/dz and Bz = d(r Aphi)/dr / r, which are checked with central differences.
+	relTolerance := 1e-7
+	current, a, h := 100., 0.1, 1e-5
+
+	tt := []struct {
+		name string
+		r, z float64
+	}{
+		{name: "near_axis", r: 1e-3, z: 0.02},
+		{name: "inside", r: 0.05, z: 0},
+		{name: "off_plane", r: 0.08, z: 0.03},
+		{name: "outside", r: 0.2, z: -0.1},
+		{name: "far", r: 1, z: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			A := func(r, z float64) float64 { return CalculateVectorPotentialFromLoop(current, a, r, z) }
+			br := -(A(tc.r, tc.z+h) - A(tc.r, tc.z-h)) / (2 * h)
+			bz := ((tc.r+h)*A(tc.r+h, tc.z) - (tc.r-h)*A(tc.r-h, tc.z)) / (2 * h * tc.r)
+
+			expectedBr, _, expectedBz := CalculateFieldFromLoopPolar(current, a, tc.r, tc.z)
+			scale := math.Hypot(expectedBr, expectedBz)
+			if !approxEqual(br/scale, expectedBr/scale, relTolerance) || !approxEqual(bz/scale, expectedBz/scale, relTolerance) {
+				t.Errorf("expected curl A = (%g, %g), got (%g, %g)", expectedBr, expectedBz, br, bz)
+			}
+		})
+	}
+
+	if Aphi := CalculateVectorPotentialFromLoop(current, a, 0, 0.1); Aphi != 0 {
+		t.Errorf("expected 0 on axis, got %g", Aphi)
+	}
+}
+
+func TestVectorPotentialFromLoopNearAxis(t *testing.T) {
+	// Close to the axis Aphi = r Bz(0, z) / 2, with Bz(0, z) = mu0 I a^2 / (2 (a^2 + z^2)^(3/2)).
+	relTolerance := 1e-9
+	current, a, z := 100., 0.1, 0.05
+
+	for _, r := range []float64{1e-9, 1e-6, 1e-4} {
+		expected := r / 2 * mu0 * current * a * a / (2 * math.Pow(a*a+z*z, 1.5))
+		if actual := CalculateVectorPotentialFromLoop(current, a, r, z); !approxEqual(actual/expected, 1, relTolerance) {
+			t.Errorf("r = %g: expected %g, got %g", r, expected, actual)
+		}
+	}
+}
+
+func TestSolenoidVectorPotential(t *testing.T) {
+	tolerance := 1e-15
+	solenoid := NewSolenoid(0.1, 0.12, 0.3, 100, 0.05, 20, 2)
+
+	polar := NewPolarPoint(0.05, 0.7, 0.1)
+	ar, aphi, az := solenoid.CalculateVectorPotentialAtPoint(polar)
+	if ar != 0 || az != 0 {
+		t.Errorf("expected only Aphi, got (%g, %g, %g)", ar, aphi, az)
+	}
+
+	var expected float64
+	for i := 0; i < solenoid.Nlayers; i++ {
+		radius := 0.1 + (float64(i)+0.5)*0.01
+		for j := 0; j < solenoid.Nturns; j++ {
+			expected += CalculateVectorPotentialFromLoop(100, radius, 0.05, 0.1-(0.05-0.15+(float64(j)+0.5)*0.015))
+		}
+	}
+	if !approxEqual(aphi, expected, tolerance) {
+		t.Errorf("expected Aphi = %g, got %g", expected, aphi)
+	}
+
+	ax, ay, _ := solenoid.CalculateVectorPotentialAtPoint(NewCartesianPoint(polar.GetCartesianCoordinates()))
+	if !approxEqual(ax, -aphi*math.Sin(0.7), tolerance) || !approxEqual(ay, aphi*math.Cos(0.7), tolerance) {
+		t.Errorf("expected (%g, %g), got (%g, %g)", -aphi*math.Sin(0.7), aphi*math.Cos(0.7), ax, ay)
+	}
+
+	// Moving the solenoid along its axis and the point with it leaves the potential unchanged.
+	solenoid.Placement = NewPlacement(Vec3{0, 0, 1}, IdentityRotation())
+	_, moved, _ := solenoid.CalculateVectorPotentialAtPoint(NewPolarPoint(0.05, 0.7, 1.1))
+	if !approxEqual(moved, aphi, tolerance) {
+		t.Errorf("expected Aphi = %g with placement, got %g", aphi, moved)
+	}
+}
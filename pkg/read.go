@@ -0,0 +1,185 @@
+package golenoid
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// This file contains readers for the formats written by the Field writers.
+// The source of a field cannot be reconstructed, so Field.Source is always nil in a field that has been read.
+
+// ReadFromFile reads a field from the file called name in the given format.
+func ReadFromFile(name string, format FileFormat) (*Field, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch format {
+	case FormatCSV:
+		return ReadCSV(file)
+	case FormatJSON:
+		return ReadJSON(file)
+	case FormatBinary:
+		return ReadBinary(file)
+	default:
+		return nil, fmt.Errorf("unsupported file format: %v", format)
+	}
+}
+
+// coordinatesFromColumns returns the coordinate system that writes the given column names.
+func coordinatesFromColumns(names []string) (CoordinateSystem, error) {
+	for _, coords := range []CoordinateSystem{CoordinatesPolar, CoordinatesCartesian} {
+		expected, _, _ := coords.columns()
+		if strings.Join(names, ",") == strings.Join(expected, ",") {
+			return coords, nil
+		}
+	}
+	return 0, fmt.Errorf("unrecognised columns: %q", names)
+}
+
+// newPoint creates a point of the concrete type for the coordinate system from the written values.
+func (c CoordinateSystem) newPoint(values [6]float64) FieldPoint {
+	if c == CoordinatesCartesian {
+		p := NewCartesianPoint(values[0], values[1], values[2])
+		p.SetFieldCartesian(values[3], values[4], values[5])
+		return p
+	}
+	p := NewPolarPoint(values[0], values[1], values[2])
+	p.SetFieldPolar(values[3], values[4], values[5])
+	return p
+}
+
+// ReadCSV reads a field written by WriteCSV.
+//
+// Points are returned as *PolarPoint or *CartesianPoint according to the columns in the file.
+func ReadCSV(r io.Reader) (*Field, error) {
+	br := bufio.NewReader(r)
+	field := &Field{}
+
+	// The header is made of comment lines, of which only the shape of the grid is needed.
+	for {
+		b, err := br.Peek(1)
+		if err != nil || b[0] != '#' {
+			break
+		}
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if dims, ok := strings.CutPrefix(strings.TrimSpace(line), "# dims:"); ok {
+			if _, err := fmt.Sscan(dims, &field.Dims[0], &field.Dims[1], &field.Dims[2]); err != nil {
+				return nil, fmt.Errorf("invalid dims %q: %w", dims, err)
+			}
+		}
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = 6
+	names, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading column names: %w", err)
+	}
+	coords, err := coordinatesFromColumns(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var values [6]float64
+		for i, s := range record {
+			if values[i], err = strconv.ParseFloat(s, 64); err != nil {
+				line, _ := cr.FieldPos(i)
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		field.Points = append(field.Points, coords.newPoint(values))
+	}
+	return field, nil
+}
+
+// ReadJSON reads a field written by WriteJSON.
+//
+// Values written as null are read as NaN.
+//
+// Points are returned as *PolarPoint or *CartesianPoint according to the columns in the file.
+func ReadJSON(r io.Reader) (*Field, error) {
+	var in jsonField
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, err
+	}
+	coords, err := coordinatesFromColumns(in.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &Field{Points: make([]FieldPoint, len(in.Points))}
+	if in.Dims != nil {
+		if len(in.Dims) != 3 {
+			return nil, fmt.Errorf("invalid dims: %v", in.Dims)
+		}
+		copy(field.Dims[:], in.Dims)
+	}
+	for i, point := range in.Points {
+		var values [6]float64
+		for j, v := range point {
+			values[j] = float64(v)
+		}
+		field.Points[i] = coords.newPoint(values)
+	}
+	return field, nil
+}
+
+// ReadBinary reads a field written by WriteBinary.
+//
+// Points are returned as *PolarPoint or *CartesianPoint according to the coordinate system in the header.
+func ReadBinary(r io.Reader) (*Field, error) {
+	br := bufio.NewReader(r)
+
+	var header binaryHeader
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if string(header.Magic[:]) != binaryMagic {
+		return nil, errors.New("not a golenoid binary field")
+	}
+	if header.Version != binaryVersion {
+		return nil, fmt.Errorf("unsupported binary version: %d", header.Version)
+	}
+	coords := CoordinateSystem(header.Coordinates)
+	if _, _, err := coords.columns(); err != nil {
+		return nil, err
+	}
+	if _, err := br.Discard(int(header.SourceLen)); err != nil {
+		return nil, fmt.Errorf("reading source description: %w", err)
+	}
+
+	field := &Field{}
+	for i, d := range header.Dims {
+		field.Dims[i] = int(d)
+	}
+	for i := uint64(0); i < header.Points; i++ {
+		var values [6]float64
+		if err := binary.Read(br, binary.LittleEndian, &values); err != nil {
+			return nil, fmt.Errorf("reading point %d: %w", i, err)
+		}
+		field.Points = append(field.Points, coords.newPoint(values))
+	}
+	return field, nil
+}
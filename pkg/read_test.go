@@ -0,0 +1,114 @@
+package golenoid
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestFieldRoundTrip(t *testing.T) {
+	source := NewLoop(0.2, 10, 0)
+	polar := NewFieldGridPolar(0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 3, 5)
+	CalculateFullField(source, polar)
+
+	cartesian := NewField(2)
+	cartesian.Points[0] = NewCartesianPoint(0.1, -0.05, 0.3)
+	cartesian.Points[1] = NewCartesianPoint(0, 0, 0)
+	CalculateFullField(source, cartesian)
+
+	type writeFunc func(f *Field, w io.Writer, coords CoordinateSystem) error
+	type readFunc func(r io.Reader) (*Field, error)
+
+	formats := []struct {
+		name  string
+		write writeFunc
+		read  readFunc
+	}{
+		{name: "csv", write: (*Field).WriteCSV, read: ReadCSV},
+		{name: "json", write: (*Field).WriteJSON, read: ReadJSON},
+		{name: "binary", write: (*Field).WriteBinary, read: ReadBinary},
+	}
+	fields := []struct {
+		name   string
+		field  *Field
+		coords CoordinateSystem
+	}{
+		{name: "polar", field: polar, coords: CoordinatesPolar},
+		{name: "cartesian", field: cartesian, coords: CoordinatesCartesian},
+	}
+
+	for _, format := range formats {
+		for _, tc := range fields {
+			t.Run(format.name+"_"+tc.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := format.write(tc.field, &buf, tc.coords); err != nil {
+					t.Fatalf("unexpected error writing: %v", err)
+				}
+				actual, err := format.read(&buf)
+				if err != nil {
+					t.Fatalf("unexpected error reading: %v", err)
+				}
+				checkFieldsEqual(t, tc.field, actual, tc.coords)
+			})
+		}
+	}
+}
+
+func TestFieldFileRoundTrip(t *testing.T) {
+	field := NewFieldGridPolar(0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 3, 5)
+	CalculateFullField(NewLoop(0.2, 10, 0), field)
+
+	for _, format := range []FileFormat{FormatCSV, FormatJSON, FormatBinary} {
+		t.Run(format.String(), func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "field."+format.String())
+			if err := field.WriteToFile(name, format, CoordinatesPolar); err != nil {
+				t.Fatalf("unexpected error writing: %v", err)
+			}
+			actual, err := ReadFromFile(name, format)
+			if err != nil {
+				t.Fatalf("unexpected error reading: %v", err)
+			}
+			checkFieldsEqual(t, field, actual, CoordinatesPolar)
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, err := ReadCSV(bytes.NewBufferString("# points: 1\na,b,c,d,e,f\n")); err == nil {
+		t.Errorf("expected an error for unknown columns")
+	}
+	if _, err := ReadCSV(bytes.NewBufferString("r,phi,z,Br,Bphi,Bz\n1,2,3,4,5,x\n")); err == nil {
+		t.Errorf("expected an error for a malformed value")
+	}
+	if _, err := ReadBinary(bytes.NewBufferString("not a binary field at all, honestly")); err == nil {
+		t.Errorf("expected an error for a bad magic number")
+	}
+}
+
+// checkFieldsEqual fails the test unless actual has the same shape, point types and values as expected.
+func checkFieldsEqual(t *testing.T, expected, actual *Field, coords CoordinateSystem) {
+	t.Helper()
+	if actual.Dims != expected.Dims {
+		t.Errorf("expected dims %v, got %v", expected.Dims, actual.Dims)
+	}
+	if len(actual.Points) != len(expected.Points) {
+		t.Fatalf("expected %d points, got %d", len(expected.Points), len(actual.Points))
+	}
+	for i := range expected.Points {
+		switch actual.Points[i].(type) {
+		case *PolarPoint:
+			if coords != CoordinatesPolar {
+				t.Fatalf("point %d: expected a *CartesianPoint, got %T", i, actual.Points[i])
+			}
+		case *CartesianPoint:
+			if coords != CoordinatesCartesian {
+				t.Fatalf("point %d: expected a *PolarPoint, got %T", i, actual.Points[i])
+			}
+		}
+		if e, a := coords.values(expected.Points[i]), coords.values(actual.Points[i]); e != a {
+			t.Errorf("point %d: expected %v, got %v", i, e, a)
+		}
+	}
+}
@@ -14,6 +14,15 @@ type Solenoid struct {
 	Nturns    int     // Number of turns per layer in solenoid layer
 	Nlayers   int     // Number of layers in solenoid
 	CentrePos float64 // Position of centre of solenoid along z-axis
+
+	// Layers optionally describes a graded winding, where every layer has its own geometry and current.
+	// If it is not empty it replaces Rinner, Router, Length, Nturns and Nlayers, which are then ignored.
+	Layers []Layer
+
+	// Placement optionally moves and rotates the solenoid away from the z-axis.
+	// CentrePos is applied in the local frame of the solenoid before the placement.
+	// A nil Placement means the magnetic axis lies along the z-axis.
+	Placement *Placement
 }
 
 // NewSolenoid creates a new Solenoid with the given parameters.
@@ -29,105 +38,133 @@ func NewSolenoid(rInner, rOuter, length, current, centre float64, nTurns, nLayer
 	}
 }
 
-// TODO @JoeLanglands figure out how to parallelise this better. The mutex way is slower probably because of the locking.
-// Figure out a way to use workers and channels etc (read your book.)
-// You also have to achieve a balance because you could be spawning to many goroutines.
-
+// CalculateFullField calculates the field of the solenoid at every point in field.
 func (s *Solenoid) CalculateFullField(field *Field) {
-	var wg sync.WaitGroup
-	for i, p := range field.Points {
-		wg.Add(1)
-		go func(fp FieldPoint, i int) {
-			defer wg.Done()
-			// r, phi, z := fp.GetPolarCoordinates()
-			Bi, Bj, Bk := s.CalculateFieldAtPointSeq(fp)
-			// fmt.Printf("Point %d: P = (%f, %f, %f); B = (%f, %f, %f)\n", i, r, phi, z, Bi, Bj, Bk)
-			switch p := fp.(type) {
-			case *CartesianPoint:
-				p.SetFieldCartesian(Bi, Bj, Bk) // Bear in mind this will not actually work because we're passing in copies
-			case *PolarPoint:
-				p.SetFieldPolar(Bi, Bj, Bk)
-			default:
-				panic(fmt.Sprintf("Unsupported point type: %T", p))
-			}
-		}(p, i)
+	CalculateFullField(s, field)
+}
+
+// BoundingBox returns the corners of an axis aligned box in the global frame that contains the windings.
+func (s *Solenoid) BoundingBox() (min, max Vec3) {
+	_, rMax, zMin, zMax := s.envelope()
+	return boundingBox(
+		Vec3{-rMax, -rMax, s.CentrePos + zMin},
+		Vec3{rMax, rMax, s.CentrePos + zMax},
+		s.Placement,
+	)
+}
+
+// Description returns a human readable description of the solenoid and its parameters.
+func (s *Solenoid) Description() string {
+	d := fmt.Sprintf("Solenoid{Rinner: %g m, Router: %g m, Length: %g m, Current: %g A, Nturns: %d, Nlayers: %d, CentrePos: %g m}",
+		s.Rinner, s.Router, s.Length, s.Current, s.Nturns, s.Nlayers, s.CentrePos)
+	if s.Graded() {
+		d = fmt.Sprintf("Solenoid{Current: %g A, CentrePos: %g m, Layers: [%s]}", s.Current, s.CentrePos, s.describeLayers())
 	}
-	wg.Wait()
+	if s.Placement != nil {
+		d += fmt.Sprintf(" placed at %v with rotation %v", s.Placement.Translation, s.Placement.Rotation)
+	}
+	return d
 }
 
 // CalculateFieldAtPoint calculates the magnetic field at the point fp induced by the solenoid.
+//
+// If the solenoid has a Placement, fp is transformed into the frame of the coil before the
+// calculation and the field is rotated back into the global frame.
+// Points on the magnetic axis use the closed form of OnAxisField rather than summing every loop, unless the winding
+// is too thin or too coarse for its block of uniform current density to stand in for the loops (see onAxisOr).
 func (s *Solenoid) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
-	zStart := s.CentrePos - s.Length/2
-	height := s.Router - s.Rinner
+	calc := s.onAxisOr(s.calculateLocalFieldAtPoint)
+	if s.Placement != nil {
+		return s.Placement.transformField(fp, calc)
+	}
+	return calc(fp)
+}
 
-	loopSeparation := s.Length / float64(s.Nturns)
-	layerSeparation := height / float64(s.Nlayers)
+// closedFormResolution is the largest pitch between the turns, and the largest thickness of a layer, relative to the
+// radius of the layer for which the closed form of OnAxisField is used on the axis. The closed form then agrees with
+// the sum over the loops to better than about 1e-3.
+const closedFormResolution = 0.1
+
+// onAxisOr returns a local field calculation that uses OnAxisField for points on the magnetic axis and calc elsewhere.
+//
+// A winding with no radial build has no block to take the closed form of, and the loops of a coarse winding with few
+// turns or thick layers are too far apart for the block to approximate them, which would make the field jump between
+// the axis and points just off it. Such windings sum the on-axis field of their loops instead, which is cheap as they
+// have few loops for their size.
+func (s *Solenoid) onAxisOr(calc func(FieldPoint) (float64, float64, float64)) func(FieldPoint) (float64, float64, float64) {
+	onAxis := s.OnAxisField
+	for _, l := range s.windingLayers() {
+		if l.Thickness == 0 || l.Thickness > closedFormResolution*l.Radius || l.pitch() > closedFormResolution*l.Radius {
+			onAxis = s.onAxisLoopSum
+			break
+		}
+	}
 
-	Bi, Bj, Bk = s.calculateFieldOverLayers(fp, layerSeparation, loopSeparation, zStart, s.Rinner+0.5*layerSeparation)
+	return func(fp FieldPoint) (Bi, Bj, Bk float64) {
+		// Br and Bphi vanish on the axis, where the cartesian and polar components of the field coincide.
+		// Points that a Placement puts on the axis up to rounding are treated as on it, as Br only grows linearly with r.
+		if r, _, z := fp.GetPolarCoordinates(); r < axisymmetricTolerance {
+			return 0, 0, onAxis(z)
+		}
+		return calc(fp)
+	}
+}
 
-	return
+// calculateLocalFieldAtPoint calculates the field at fp in the frame of the coil.
+func (s *Solenoid) calculateLocalFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	return s.calculateFieldOverLayers(fp, s.windingLayers())
 }
 
 // calculateFieldOverLayers calculates the field at p over all layers in a solenoid.
-func (s *Solenoid) calculateFieldOverLayers(fp FieldPoint, layerSep, loopSep, zStart, firstLoopR float64) (Bi, Bj, Bk float64) {
-	loopRadius := firstLoopR - layerSep
-	// var mu sync.Mutex
+func (s *Solenoid) calculateFieldOverLayers(fp FieldPoint, layers []Layer) (Bi, Bj, Bk float64) {
+	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	wg.Add(s.Nlayers)
-	for i := 0; i < s.Nlayers; i++ {
-		loopRadius += layerSep
-		// i := i
-		go func(r float64) {
-			// fmt.Printf("Calculating layer %d out of %d\n", i, s.Nlayers)
+	wg.Add(len(layers))
+	for _, l := range layers {
+		go func(l Layer) {
 			defer wg.Done()
-			bi, bj, bk := s.calculateFieldOverLoops(fp, loopSep, zStart, r)
-			// mu.Lock()
+			bi, bj, bk := s.calculateFieldOverLoops(fp, l)
+			mu.Lock()
 			Bi += bi
 			Bj += bj
 			Bk += bk
-			// mu.Unlock()
-		}(loopRadius)
+			mu.Unlock()
+		}(l)
 	}
 	wg.Wait()
 	return
 }
 
 // calculateFieldOverLoops calculates the field at p over all loops in a layer.
-func (s *Solenoid) calculateFieldOverLoops(fp FieldPoint, loopSep, zStart, loopRadius float64) (Bi, Bj, Bk float64) {
-	var newZ float64
-	offset := zStart - loopSep
+func (s *Solenoid) calculateFieldOverLoops(fp FieldPoint, l Layer) (Bi, Bj, Bk float64) {
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	wg.Add(s.Nturns)
-	for i := 0; i < s.Nturns; i++ {
-		offset += loopSep
-		// i := i
+	wg.Add(l.Nturns)
+	for i := 0; i < l.Nturns; i++ {
+		offset := l.turnZ(s.CentrePos, i)
 		switch p := fp.(type) {
 		case *CartesianPoint:
-			newZ = p.Z - offset
 			go func(z float64) {
-				// fmt.Printf("Calculating loop %d out of %d\n", i+1, s.Nturns)
 				defer wg.Done()
-				bx, by, bz := CalculateFieldFromLoopCartesian(s.Current, loopRadius, p.X, p.Y, z)
+				bx, by, bz := CalculateFieldFromLoopCartesian(l.Current, l.Radius, p.X, p.Y, z)
 				mu.Lock()
 				Bi += bx
 				Bj += by
 				Bk += bz
 				mu.Unlock()
-			}(newZ)
+			}(p.Z - offset)
 		case *PolarPoint:
-			newZ = p.Z - offset
 			go func(z float64) {
 				defer wg.Done()
-				br, bphi, bz := CalculateFieldFromLoopPolar(s.Current, loopRadius, p.R, z)
+				br, bphi, bz := CalculateFieldFromLoopPolar(l.Current, l.Radius, p.R, z)
 				mu.Lock()
 				Bi += br
 				Bj += bphi
 				Bk += bz
 				mu.Unlock()
-			}(newZ)
+			}(p.Z - offset)
 		default:
 			panic(fmt.Sprintf("Unsupported point type: %T", p))
 		}
@@ -136,44 +173,29 @@ func (s *Solenoid) calculateFieldOverLoops(fp FieldPoint, loopSep, zStart, loopR
 	return
 }
 
+// CalculateFieldPoint calculates the field of the solenoid at fp, stores it in fp and returns fp.
 func (s *Solenoid) CalculateFieldPoint(fp FieldPoint) FieldPoint {
-	zStart := s.CentrePos - s.Length/2
-	height := s.Router - s.Rinner
-
-	loopSeparation := s.Length / float64(s.Nturns)
-	layerSeparation := height / float64(s.Nlayers)
-
-	Bi, Bj, Bk := s.calculateFieldOverLayersSeq(fp, layerSeparation, loopSeparation, zStart, s.Rinner+0.5*layerSeparation)
-	switch p := fp.(type) {
-	case *CartesianPoint:
-		p.SetFieldCartesian(Bi, Bj, Bk) // Bear in mind this will not actually work because we're passing in copies
-	case *PolarPoint:
-		p.SetFieldPolar(Bi, Bj, Bk)
-	default:
-		panic(fmt.Sprintf("Unsupported point type: %T", p))
-	}
-	return fp
+	return CalculateFieldPoint(s, fp)
 }
 
+// CalculateFieldAtPointSeq calculates the magnetic field at the point fp induced by the solenoid without spawning
+// any goroutines. It is otherwise identical to CalculateFieldAtPoint.
 func (s *Solenoid) CalculateFieldAtPointSeq(fp FieldPoint) (Bi, Bj, Bk float64) {
-	zStart := s.CentrePos - s.Length/2
-	height := s.Router - s.Rinner
-
-	loopSeparation := s.Length / float64(s.Nturns)
-	layerSeparation := height / float64(s.Nlayers)
-
-	Bi, Bj, Bk = s.calculateFieldOverLayersSeq(fp, layerSeparation, loopSeparation, zStart, s.Rinner+0.5*layerSeparation)
-
-	return
+	calc := s.onAxisOr(s.calculateLocalFieldAtPointSeq)
+	if s.Placement != nil {
+		return s.Placement.transformField(fp, calc)
+	}
+	return calc(fp)
 }
 
-func (s *Solenoid) calculateFieldOverLayersSeq(fp FieldPoint, layerSep, loopSep, zStart, firstLoopR float64) (Bi, Bj, Bk float64) {
-	loopRadius := firstLoopR - layerSep
+// calculateLocalFieldAtPointSeq calculates the field at fp in the frame of the coil without spawning goroutines.
+func (s *Solenoid) calculateLocalFieldAtPointSeq(fp FieldPoint) (Bi, Bj, Bk float64) {
+	return s.calculateFieldOverLayersSeq(fp, s.windingLayers())
+}
 
-	for i := 0; i < s.Nlayers; i++ {
-		loopRadius += layerSep
-		// fmt.Printf("Calculating layer %d out of %d\n", i, s.Nlayers)
-		bi, bj, bk := s.calculateFieldOverLoopsSeq(fp, loopSep, zStart, loopRadius)
+func (s *Solenoid) calculateFieldOverLayersSeq(fp FieldPoint, layers []Layer) (Bi, Bj, Bk float64) {
+	for _, l := range layers {
+		bi, bj, bk := s.calculateFieldOverLoopsSeq(fp, l)
 		Bi += bi
 		Bj += bj
 		Bk += bk
@@ -181,23 +203,17 @@ func (s *Solenoid) calculateFieldOverLayersSeq(fp FieldPoint, layerSep, loopSep,
 	return
 }
 
-func (s *Solenoid) calculateFieldOverLoopsSeq(fp FieldPoint, loopSep, zStart, loopRadius float64) (Bi, Bj, Bk float64) {
-	var newZ float64
-	offset := zStart - loopSep
-
-	for i := 0; i < s.Nturns; i++ {
-		offset += loopSep
-		// fmt.Println("Calculating loop", i, "out of", s.Nturns)
+func (s *Solenoid) calculateFieldOverLoopsSeq(fp FieldPoint, l Layer) (Bi, Bj, Bk float64) {
+	for i := 0; i < l.Nturns; i++ {
+		offset := l.turnZ(s.CentrePos, i)
 		switch p := fp.(type) {
 		case *CartesianPoint:
-			newZ = p.Z - offset
-			bx, by, bz := CalculateFieldFromLoopCartesian(s.Current, loopRadius, p.X, p.Y, newZ)
+			bx, by, bz := CalculateFieldFromLoopCartesian(l.Current, l.Radius, p.X, p.Y, p.Z-offset)
 			Bi += bx
 			Bj += by
 			Bk += bz
 		case *PolarPoint:
-			newZ = p.Z - offset
-			br, bphi, bz := CalculateFieldFromLoopPolar(s.Current, loopRadius, p.R, newZ)
+			br, bphi, bz := CalculateFieldFromLoopPolar(l.Current, l.Radius, p.R, p.Z-offset)
 			Bi += br
 			Bj += bphi
 			Bk += bz
@@ -208,94 +224,17 @@ func (s *Solenoid) calculateFieldOverLoopsSeq(fp FieldPoint, loopSep, zStart, lo
 	return
 }
 
-func generatePoints(rMin, rMax, phiMin, phiMax, zMin, zMax float64, nr, nphi, nz int) <-chan FieldPoint {
-	pointStream := make(chan FieldPoint)
-
-	go func() {
-		defer close(pointStream)
-
-		for r := rMin; r <= rMax; r += (rMax - rMin) / float64(nr) {
-			for phi := phiMin; phi <= phiMax; phi += (phiMax - phiMin) / float64(nphi) {
-				for z := zMin; z <= zMax; z += (zMax - zMin) / float64(nz) {
-					pointStream <- NewPolarPoint(r, phi, z)
-				}
-			}
-		}
-	}()
-	return pointStream
-}
-
-func (s *Solenoid) calcField(pointStream <-chan FieldPoint) <-chan FieldPoint {
-	resultField := make(chan FieldPoint)
-
-	go func() {
-		defer close(resultField)
-		for fp := range pointStream {
-			zStart := s.CentrePos - s.Length/2
-			height := s.Router - s.Rinner
-
-			loopSeparation := s.Length / float64(s.Nturns)
-			layerSeparation := height / float64(s.Nlayers)
-
-			Bi, Bj, Bk := s.calculateFieldOverLayersSeq(fp, layerSeparation, loopSeparation, zStart, s.Rinner+0.5*layerSeparation)
-			switch p := fp.(type) {
-			case *CartesianPoint:
-				p.SetFieldCartesian(Bi, Bj, Bk)
-			case *PolarPoint:
-				p.SetFieldPolar(Bi, Bj, Bk)
-			default:
-				panic(fmt.Sprintf("Unsupported point type: %T", p))
-			}
-
-			resultField <- fp
+// forEachLoop calls fn with the radius, the z position in the frame of the coil and the current of every turn of
+// every layer. The loops are positioned exactly as in the field calculation.
+func (s *Solenoid) forEachLoop(fn func(radius, z, current float64)) {
+	for _, l := range s.windingLayers() {
+		for j := 0; j < l.Nturns; j++ {
+			fn(l.Radius, l.turnZ(s.CentrePos, j), l.Current)
 		}
-	}()
-
-	return resultField
-}
-
-func accumulateResults(resultChans ...<-chan FieldPoint) <-chan FieldPoint {
-	accumulatedResults := make(chan FieldPoint)
-
-	var wg sync.WaitGroup
-	wg.Add(len(resultChans))
-
-	for _, c := range resultChans {
-		go func(c <-chan FieldPoint) {
-			defer wg.Done()
-			for fp := range c {
-				accumulatedResults <- fp
-			}
-		}(c)
 	}
-
-	go func() {
-		wg.Wait()
-		close(accumulatedResults)
-	}()
-
-	return accumulatedResults
 }
 
+// CalculateFieldWithWorkers calculates the field of the solenoid over a polar grid using numWorkers goroutines.
 func (s *Solenoid) CalculateFieldWithWorkers(rMin, rMax, phiMin, phiMax, zMin, zMax float64, nr, nphi, nz, numWorkers int) *Field {
-	fieldStream := generatePoints(rMin, rMax, phiMin, phiMax, zMin, zMax, nr, nphi, nz)
-
-	// fan out
-	workerChannels := make([]<-chan FieldPoint, numWorkers)
-	for i := 0; i < numWorkers; i++ {
-		workerChannels[i] = s.calcField(fieldStream)
-	}
-
-	// fan in
-	resultChan := accumulateResults(workerChannels...)
-
-	field := Field{
-		Points: make([]FieldPoint, 0, nr*nphi*nz),
-	}
-
-	for fp := range resultChan {
-		field.Points = append(field.Points, fp)
-	}
-
-	return &field
+	return CalculateFieldWithWorkers(s, rMin, rMax, phiMin, phiMax, zMin, zMax, nr, nphi, nz, numWorkers)
 }
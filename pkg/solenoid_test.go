@@ -7,10 +7,12 @@ import (
 
 var resultBx, resultBy, resultBz float64
 
+// The benchmarks use points just off the magnetic axis, where the field is found by summing every loop.
+
 func BenchmarkCalculateFieldConcurrentlyPolar(b *testing.B) {
 	var bi, bj, bk float64
 	solenoid := NewSolenoid(0.25, 0.28, 1.31, 200, 0, 768, 64)
-	fpPolar := NewPolarPoint(0, 0, 0)
+	fpPolar := NewPolarPoint(0.01, 0, 0)
 
 	for i := 0; i < b.N; i++ {
 		bi, bj, bk = solenoid.CalculateFieldAtPoint(fpPolar)
@@ -21,7 +23,7 @@ func BenchmarkCalculateFieldConcurrentlyPolar(b *testing.B) {
 func BenchmarkCalculateFieldConcurrentlyCartesian(b *testing.B) {
 	var bi, bj, bk float64
 	solenoid := NewSolenoid(0.25, 0.28, 1.31, 200, 0, 768, 64)
-	fpCart := NewCartesianPoint(0, 0, 0)
+	fpCart := NewCartesianPoint(0.01, 0, 0)
 
 	for i := 0; i < b.N; i++ {
 		bi, bj, bk = solenoid.CalculateFieldAtPoint(fpCart)
@@ -32,7 +34,7 @@ func BenchmarkCalculateFieldConcurrentlyCartesian(b *testing.B) {
 func BenchmarkCalculateFieldSequentiallyPolar(b *testing.B) {
 	var bi, bj, bk float64
 	solenoid := NewSolenoid(0.25, 0.28, 1.31, 200, 0, 768, 64)
-	fpPolar := NewPolarPoint(0, 0, 0)
+	fpPolar := NewPolarPoint(0.01, 0, 0)
 
 	for i := 0; i < b.N; i++ {
 		bi, bj, bk = solenoid.CalculateFieldAtPointSeq(fpPolar)
@@ -43,7 +45,7 @@ func BenchmarkCalculateFieldSequentiallyPolar(b *testing.B) {
 func BenchmarkCalculateFieldSequentiallyCartesian(b *testing.B) {
 	var bi, bj, bk float64
 	solenoid := NewSolenoid(0.25, 0.28, 1.31, 200, 0, 768, 64)
-	fpCart := NewCartesianPoint(0, 0, 0)
+	fpCart := NewCartesianPoint(0.01, 0, 0)
 
 	for i := 0; i < b.N; i++ {
 		bi, bj, bk = solenoid.CalculateFieldAtPointSeq(fpCart)
@@ -71,3 +73,26 @@ func BenchmarkCalculateFullField(b *testing.B) {
 		solenoid.CalculateFullField(field)
 	}
 }
+
+func TestLoopsSymmetricAboutCentre(t *testing.T) {
+	tolerance := 1e-15
+	// With only a few turns, loops offset from the centre of their share of the length would break the symmetry.
+	solenoid := NewSolenoid(0.1, 0.11, 0.05, 100, 0.2, 5, 2)
+
+	for _, fp := range []struct{ r, z float64 }{{0, 0.01}, {0.05, 0.02}, {0.2, 0.1}} {
+		above := NewPolarPoint(fp.r, 0, solenoid.CentrePos+fp.z)
+		below := NewPolarPoint(fp.r, 0, solenoid.CentrePos-fp.z)
+		for name, calc := range map[string]func(FieldPoint) (float64, float64, float64){
+			"concurrent": solenoid.CalculateFieldAtPoint,
+			"sequential": solenoid.CalculateFieldAtPointSeq,
+		} {
+			brAbove, _, bzAbove := calc(above)
+			brBelow, _, bzBelow := calc(below)
+			// Bz is even and Br is odd about the centre of the coil.
+			if !approxEqual(bzAbove, bzBelow, tolerance) || !approxEqual(brAbove, -brBelow, tolerance) {
+				t.Errorf("%s at r = %g, z = ±%g: got (%g, %g) above and (%g, %g) below",
+					name, fp.r, fp.z, brAbove, bzAbove, brBelow, bzBelow)
+			}
+		}
+	}
+}
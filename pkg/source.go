@@ -0,0 +1,208 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// FieldSource is an interface for anything that induces a magnetic field.
+//
+// Grid evaluation and the worker pool work against this interface, so any source can be plugged
+// into the whole pipeline.
+type FieldSource interface {
+	// CalculateFieldAtPoint calculates the magnetic field at fp in the coordinate system of fp.
+	CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64)
+	// BoundingBox returns the corners of an axis aligned box in the global frame that contains the conductors of the source.
+	BoundingBox() (min, max Vec3)
+	// Description returns a human readable description of the source and its parameters.
+	Description() string
+}
+
+var (
+	_ FieldSource = (*Solenoid)(nil)
+	_ FieldSource = (*Loop)(nil)
+	_ FieldSource = (*MagnetSystem)(nil)
+	_ FieldSource = (*HelicalWinding)(nil)
+)
+
+// sequentialFieldSource is implemented by sources that also provide a calculation that does not spawn goroutines.
+//
+// The pipeline already parallelises over points, so it prefers the sequential calculation where available.
+type sequentialFieldSource interface {
+	CalculateFieldAtPointSeq(fp FieldPoint) (Bi, Bj, Bk float64)
+}
+
+// calculateFieldSeq calculates the field of src at fp, sequentially if src supports it.
+func calculateFieldSeq(src FieldSource, fp FieldPoint) (Bi, Bj, Bk float64) {
+	if s, ok := src.(sequentialFieldSource); ok {
+		return s.CalculateFieldAtPointSeq(fp)
+	}
+	return src.CalculateFieldAtPoint(fp)
+}
+
+// setField sets the field of fp from components given in the coordinate system of fp.
+func setField(fp FieldPoint, Bi, Bj, Bk float64) {
+	switch p := fp.(type) {
+	case *CartesianPoint:
+		p.SetFieldCartesian(Bi, Bj, Bk)
+	case *PolarPoint:
+		p.SetFieldPolar(Bi, Bj, Bk)
+	default:
+		panic(fmt.Sprintf("Unsupported point type: %T", p))
+	}
+}
+
+// boundingBox returns the global axis aligned box that contains the local box (min, max) after placement p.
+//
+// A nil placement leaves the box unchanged.
+func boundingBox(min, max Vec3, p *Placement) (Vec3, Vec3) {
+	if p == nil {
+		return min, max
+	}
+	gMin := Vec3{math.Inf(1), math.Inf(1), math.Inf(1)}
+	gMax := Vec3{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for i := 0; i < 8; i++ {
+		corner := min
+		for j := 0; j < 3; j++ {
+			if i&(1<<j) != 0 {
+				corner[j] = max[j]
+			}
+		}
+		g := p.ToGlobal(corner)
+		for j := 0; j < 3; j++ {
+			gMin[j] = math.Min(gMin[j], g[j])
+			gMax[j] = math.Max(gMax[j], g[j])
+		}
+	}
+	return gMin, gMax
+}
+
+// TODO @JoeLanglands figure out how to parallelise this better. The mutex way is slower probably because of the locking.
+// Figure out a way to use workers and channels etc (read your book.)
+// You also have to achieve a balance because you could be spawning to many goroutines.
+
+// CalculateFullField calculates the field of src at every point in field.
+//
+// If src is axisymmetric the field is only calculated once for each unique (r, z), see CalculateFullFieldAxisymmetric.
+func CalculateFullField(src FieldSource, field *Field) {
+	field.Source = src
+	if isAxisymmetric(src) {
+		fanOutAxisymmetric(src, field, calculateEveryPoint)
+		return
+	}
+	calculateEveryPoint(src, field)
+}
+
+// calculateEveryPoint calculates the field of src at every point in field with one goroutine per point.
+func calculateEveryPoint(src FieldSource, field *Field) {
+	var wg sync.WaitGroup
+	for _, p := range field.Points {
+		wg.Add(1)
+		go func(fp FieldPoint) {
+			defer wg.Done()
+			Bi, Bj, Bk := calculateFieldSeq(src, fp)
+			setField(fp, Bi, Bj, Bk)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// CalculateFieldPoint calculates the field of src at fp, stores it in fp and returns fp.
+func CalculateFieldPoint(src FieldSource, fp FieldPoint) FieldPoint {
+	Bi, Bj, Bk := calculateFieldSeq(src, fp)
+	setField(fp, Bi, Bj, Bk)
+	return fp
+}
+
+// indexedPoint is a point along with its position in the Field it belongs to.
+type indexedPoint struct {
+	index int
+	fp    FieldPoint
+}
+
+func generatePoints(field *Field) <-chan indexedPoint {
+	pointStream := make(chan indexedPoint)
+
+	go func() {
+		defer close(pointStream)
+
+		for i, fp := range field.Points {
+			pointStream <- indexedPoint{index: i, fp: fp}
+		}
+	}()
+	return pointStream
+}
+
+func calcField(src FieldSource, pointStream <-chan indexedPoint) <-chan indexedPoint {
+	resultField := make(chan indexedPoint)
+
+	go func() {
+		defer close(resultField)
+		for p := range pointStream {
+			CalculateFieldPoint(src, p.fp)
+			resultField <- p
+		}
+	}()
+
+	return resultField
+}
+
+func accumulateResults(resultChans ...<-chan indexedPoint) <-chan indexedPoint {
+	accumulatedResults := make(chan indexedPoint)
+
+	var wg sync.WaitGroup
+	wg.Add(len(resultChans))
+
+	for _, c := range resultChans {
+		go func(c <-chan indexedPoint) {
+			defer wg.Done()
+			for p := range c {
+				accumulatedResults <- p
+			}
+		}(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(accumulatedResults)
+	}()
+
+	return accumulatedResults
+}
+
+// CalculateFieldWithWorkers calculates the field of src over the same polar grid as NewFieldGridPolar using numWorkers goroutines.
+//
+// The points of the returned field are in grid order, whichever worker calculated them.
+func CalculateFieldWithWorkers(src FieldSource, rMin, rMax, phiMin, phiMax, zMin, zMax float64, nr, nphi, nz, numWorkers int) *Field {
+	field := NewFieldGridPolar(rMin, rMax, phiMin, phiMax, zMin, zMax, nr, nphi, nz)
+	field.Source = src
+
+	calculate := func(src FieldSource, field *Field) {
+		calculateWithWorkers(src, field, numWorkers)
+	}
+	if isAxisymmetric(src) {
+		fanOutAxisymmetric(src, field, calculate)
+	} else {
+		calculate(src, field)
+	}
+	return field
+}
+
+// calculateWithWorkers calculates the field of src at every point in field using numWorkers goroutines.
+func calculateWithWorkers(src FieldSource, field *Field, numWorkers int) {
+	fieldStream := generatePoints(field)
+
+	// fan out
+	workerChannels := make([]<-chan indexedPoint, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		workerChannels[i] = calcField(src, fieldStream)
+	}
+
+	// fan in
+	resultChan := accumulateResults(workerChannels...)
+
+	for p := range resultChan {
+		field.Points[p.index] = p.fp
+	}
+}
@@ -0,0 +1,56 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoopFieldSource(t *testing.T) {
+	tolerance := 1e-15
+	loop := NewLoop(0.2, 10, 0.5)
+
+	field := NewField(2)
+	field.Points[0] = NewPolarPoint(0.1, 0.3, 0.6)
+	field.Points[1] = NewCartesianPoint(0.1, 0, 0.6)
+	CalculateFullField(loop, field)
+
+	expectedBr, _, expectedBz := CalculateFieldFromLoopPolar(10, 0.2, 0.1, 0.1)
+	for _, p := range field.Points {
+		br, bphi, bz := p.GetPolarField()
+		if !approxEqual(br, expectedBr, tolerance) || !approxEqual(bphi, 0, tolerance) || !approxEqual(bz, expectedBz, tolerance) {
+			t.Errorf("expected (%g, 0, %g), got %v", expectedBr, expectedBz, p)
+		}
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	tolerance := 1e-12
+
+	tilted := NewSolenoid(0.1, 0.2, 1, 1, 0.5, 10, 1)
+	tilted.Placement = NewPlacement(Vec3{1, 0, 0}, NewRotationFromAxisAngle(Vec3{0, 1, 0}, math.Pi/2))
+
+	tt := []struct {
+		name        string
+		source      FieldSource
+		expectedMin Vec3
+		expectedMax Vec3
+	}{
+		{name: "solenoid", source: NewSolenoid(0.1, 0.2, 1, 1, 0.5, 10, 1), expectedMin: Vec3{-0.2, -0.2, 0}, expectedMax: Vec3{0.2, 0.2, 1}},
+		{name: "loop", source: NewLoop(0.3, 1, -1), expectedMin: Vec3{-0.3, -0.3, -1}, expectedMax: Vec3{0.3, 0.3, -1}},
+		{name: "placed_solenoid", source: tilted, expectedMin: Vec3{1, -0.2, -0.2}, expectedMax: Vec3{2, 0.2, 0.2}},
+		{name: "system", source: NewMagnetSystem(NewLoop(0.3, 1, -1), tilted), expectedMin: Vec3{-0.3, -0.3, -1}, expectedMax: Vec3{2, 0.3, 0.2}},
+		{name: "empty_system", source: NewMagnetSystem(), expectedMin: Vec3{}, expectedMax: Vec3{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			min, max := tc.source.BoundingBox()
+			for i := 0; i < 3; i++ {
+				if !approxEqual(min[i], tc.expectedMin[i], tolerance) || !approxEqual(max[i], tc.expectedMax[i], tolerance) {
+					t.Errorf("expected (%v, %v), got (%v, %v)", tc.expectedMin, tc.expectedMax, min, max)
+					break
+				}
+			}
+		})
+	}
+}
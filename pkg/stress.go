@@ -0,0 +1,132 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains first-pass estimates of the stress in the windings of a solenoid due to the Lorentz forces.
+// Every turn is treated as a free-standing ring, so the hoop stress is J Bz R (the so-called BJR stress) and no load
+// is shared between layers. This is conservative for the inner layers of a solenoid, where the outer layers and any
+// overbinding carry part of the load.
+
+// Conductor describes the cross-section and optionally the elastic properties of the conductor of a winding.
+type Conductor struct {
+	Width  float64 // Axial width of the conductor in metres
+	Height float64 // Radial height of the conductor in metres
+
+	// YoungsModulus is the Young's modulus of the conductor in pascals. It is only needed for strains and
+	// strains are not calculated if it is 0.
+	YoungsModulus float64
+	// PoissonRatio is the Poisson ratio of the conductor, used to add the effect of the axial stress on the hoop strain.
+	PoissonRatio float64
+}
+
+// Area returns the cross-sectional area of the conductor in square metres.
+func (c Conductor) Area() float64 {
+	return c.Width * c.Height
+}
+
+// TurnStress is the stress in one turn of a solenoid. Stresses are positive in tension.
+type TurnStress struct {
+	Layer  int     // Index of the layer, counting outwards from Rinner or in the order of Layers
+	Turn   int     // Index of the turn in the layer, counting from the negative end of the coil
+	Radius float64 // Radius of the turn in metres
+	Z      float64 // Position of the turn along the axis in the frame of the coil in metres
+
+	Hoop       float64 // Hoop stress in pascals
+	Axial      float64 // Axial stress in pascals due to the turns pressing on each other
+	HoopStrain float64 // Hoop strain, 0 if the Conductor has no YoungsModulus
+}
+
+// LayerStress summarises the stress in one layer of a solenoid.
+type LayerStress struct {
+	Layer    int     // Index of the layer, counting outwards from Rinner or in the order of Layers
+	Radius   float64 // Radius of the layer in metres
+	MeanHoop float64 // Mean hoop stress over the turns of the layer in pascals
+	Max      TurnStress
+}
+
+// StressReport holds the estimated stress in every turn of a solenoid.
+type StressReport struct {
+	Turns  []TurnStress  // Stress in every turn, ordered by layer and then by turn
+	Layers []LayerStress // Summary of every layer
+	Max    TurnStress    // The turn with the largest hoop stress in magnitude
+}
+
+// Stress estimates the stress in every turn of the solenoid wound from conductor c, due to the field of the solenoid
+// itself and of the external sources, see WindingForces.
+//
+// The hoop stress in a turn is its hoop tension over the area of the conductor, J Bz R with J = Current/Area.
+// The axial stress is found by accumulating the axial forces along each layer, with any net axial force on a layer
+// reacted equally at both of its ends. If the conductor has a YoungsModulus the hoop strain is
+// (Hoop - PoissonRatio*Axial) / YoungsModulus, neglecting the radial stress.
+//
+// An error is returned if the conductor has no area or does not fit in the share of the winding of a turn.
+func (s *Solenoid) Stress(c Conductor, external ...FieldSource) (*StressReport, error) {
+	if c.Width <= 0 || c.Height <= 0 {
+		return nil, fmt.Errorf("conductor must have a positive width and height, got %g by %g m", c.Width, c.Height)
+	}
+	// Allow for rounding when the conductor exactly fills the winding.
+	const fitTolerance = 1e-9
+	layers := s.windingLayers()
+	for i, l := range layers {
+		if pitch := l.pitch(); c.Width > pitch*(1+fitTolerance) {
+			return nil, fmt.Errorf("layer %d: conductor width %g m does not fit in the turn pitch %g m", i, c.Width, pitch)
+		}
+		if c.Height > l.Thickness*(1+fitTolerance) {
+			return nil, fmt.Errorf("layer %d: conductor height %g m does not fit in the layer pitch %g m", i, c.Height, l.Thickness)
+		}
+	}
+
+	forces := s.WindingForces(external...)
+	report := &StressReport{
+		Turns:  make([]TurnStress, len(forces)),
+		Layers: make([]LayerStress, len(layers)),
+	}
+
+	first := 0
+	for i, l := range layers {
+		layer := forces[first : first+l.Nturns]
+
+		// The load carried across the face below a turn, per unit length of conductor, starting with the reaction
+		// at the negative end.
+		var net float64
+		for _, f := range layer {
+			net += f.Fz
+		}
+		below := -net / 2
+
+		summary := LayerStress{Layer: i, Radius: l.Radius}
+		for j, f := range layer {
+			above := below + f.Fz
+			ts := TurnStress{
+				Layer:  f.Layer,
+				Turn:   f.Turn,
+				Radius: f.Radius,
+				Z:      f.Z,
+				Hoop:   f.HoopTension() / c.Area(),
+				// A turn pushed up from below and held from above is in compression, so the stress is the negative
+				// of the mean load through its faces over the radial height that carries it.
+				Axial: -(below + above) / 2 / c.Height,
+			}
+			if c.YoungsModulus != 0 {
+				ts.HoopStrain = (ts.Hoop - c.PoissonRatio*ts.Axial) / c.YoungsModulus
+			}
+			below = above
+
+			report.Turns[first+j] = ts
+			summary.MeanHoop += ts.Hoop / float64(l.Nturns)
+			if j == 0 || math.Abs(ts.Hoop) > math.Abs(summary.Max.Hoop) {
+				summary.Max = ts
+			}
+		}
+
+		report.Layers[i] = summary
+		first += l.Nturns
+		if i == 0 || math.Abs(summary.Max.Hoop) > math.Abs(report.Max.Hoop) {
+			report.Max = summary.Max
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,69 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStress(t *testing.T) {
+	tolerance := 1e-6
+	s := NewSolenoid(0.1, 0.12, 0.3, 200, 0, 150, 10)
+	c := Conductor{Width: 1.8e-3, Height: 1.8e-3, YoungsModulus: 100e9, PoissonRatio: 0.3}
+
+	report, err := s.Stress(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forces := s.WindingForces()
+	if len(report.Turns) != len(forces) || len(report.Layers) != s.Nlayers {
+		t.Fatalf("expected %d turns and %d layers, got %d and %d", len(forces), s.Nlayers, len(report.Turns), len(report.Layers))
+	}
+
+	for i, ts := range report.Turns {
+		f := forces[i]
+		if expected := 200 * f.Bz * f.Radius / c.Area(); !approxEqual(ts.Hoop, expected, tolerance) {
+			t.Errorf("layer %d turn %d: expected BJR stress %g, got %g", f.Layer, f.Turn, expected, ts.Hoop)
+		}
+		if expected := (ts.Hoop - 0.3*ts.Axial) / 100e9; !approxEqual(ts.HoopStrain, expected, 1e-15) {
+			t.Errorf("layer %d turn %d: expected strain %g, got %g", f.Layer, f.Turn, expected, ts.HoopStrain)
+		}
+	}
+
+	// The field, and so the hoop stress, is largest on the inner layer at the centre of the coil.
+	if report.Max.Layer != 0 || (report.Max.Turn != 74 && report.Max.Turn != 75) {
+		t.Errorf("expected the maximum at the centre of the inner layer, got %+v", report.Max)
+	}
+	if report.Max.Hoop <= 0 || report.Layers[0].Max != report.Max {
+		t.Errorf("expected a tensile maximum matching the inner layer, got %+v", report.Max)
+	}
+	if report.Layers[0].MeanHoop <= report.Layers[s.Nlayers-1].MeanHoop {
+		t.Errorf("expected the inner layer to carry more stress than the outer, got %g and %g",
+			report.Layers[0].MeanHoop, report.Layers[s.Nlayers-1].MeanHoop)
+	}
+
+	// The ends are pulled towards the centre, so the centre of every layer is in compression and the ends are free.
+	for _, layer := range report.Layers {
+		centre := report.Turns[layer.Layer*s.Nturns+s.Nturns/2]
+		end := report.Turns[layer.Layer*s.Nturns]
+		if centre.Axial >= 0 || math.Abs(end.Axial) >= math.Abs(centre.Axial) {
+			t.Errorf("layer %d: expected compression at the centre, got %g at the centre and %g at the end", layer.Layer, centre.Axial, end.Axial)
+		}
+	}
+}
+
+func TestStressInvalidConductor(t *testing.T) {
+	s := NewSolenoid(0.1, 0.12, 0.3, 200, 0, 150, 10)
+
+	for _, c := range []Conductor{
+		{Width: 0, Height: 1e-3},
+		{Width: 2.1e-3, Height: 1e-3},
+		{Width: 1e-3, Height: 2.1e-3},
+	} {
+		if _, err := s.Stress(c); err == nil {
+			t.Errorf("expected an error for %+v", c)
+		}
+	}
+	if _, err := s.Stress(Conductor{Width: 2e-3, Height: 2e-3}); err != nil {
+		t.Errorf("expected a conductor that fills the winding to fit, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains models of the critical surface of superconductors, the boundary in field, temperature and
+// current density below which a conductor is superconducting.
+// The angle of the field only matters for anisotropic conductors such as HTS tapes. It is measured between the field
+// and the broad face of the conductor, so 0 is a field parallel to the face and π/2 a field perpendicular to it.
+// For a solenoid wound from tape with its broad face parallel to the axis, this is the angle between the field and the axis.
+
+// CriticalSurface gives the critical current density of a superconductor.
+type CriticalSurface interface {
+	// Jc returns the critical current density in amperes per square metre at field b in teslas, angle theta in radians
+	// and temperature t in kelvin. It must decrease with b and is 0 where the conductor is not superconducting.
+	Jc(b, theta, t float64) float64
+}
+
+var (
+	_ CriticalSurface = CriticalCurrentDensity(nil)
+	_ CriticalSurface = (*NbTi)(nil)
+	_ CriticalSurface = (*Nb3Sn)(nil)
+	_ CriticalSurface = (*TabulatedCriticalSurface)(nil)
+)
+
+// Jc returns f(b, t), ignoring the angle of the field.
+func (f CriticalCurrentDensity) Jc(b, theta, t float64) float64 {
+	return f(b, t)
+}
+
+// NbTi is the Bottura fit of the critical surface of NbTi
+//
+//	Jc(B, T) = JcRef C0/B (B/Bc2)^Alpha (1 - B/Bc2)^Beta (1 - (T/Tc0)^N)^Gamma
+//
+// where Bc2(T) = Bc20 (1 - (T/Tc0)^N). The fit diverges as B goes to 0 and is only meant to be used above about 0.5 T.
+type NbTi struct {
+	JcRef float64 // Reference critical current density in amperes per square metre
+	C0    float64 // Normalisation constant in teslas
+	Bc20  float64 // Upper critical field at 0 K in teslas
+	Tc0   float64 // Critical temperature at 0 T in kelvin
+	Alpha float64
+	Beta  float64
+	Gamma float64
+	N     float64
+}
+
+// NewNbTiLHC creates the Bottura fit of the NbTi strands of the LHC, normalised so that Jc(5 T, 4.2 K) is close to
+// jcRef, the critical current density of the strand at 5 T and 4.2 K in amperes per square metre.
+func NewNbTiLHC(jcRef float64) *NbTi {
+	return &NbTi{
+		JcRef: jcRef,
+		C0:    27.04,
+		Bc20:  14.5,
+		Tc0:   9.2,
+		Alpha: 0.57,
+		Beta:  0.9,
+		Gamma: 2.32,
+		N:     1.7,
+	}
+}
+
+// Bc2 returns the upper critical field in teslas at temperature t in kelvin.
+func (n *NbTi) Bc2(t float64) float64 {
+	if t >= n.Tc0 {
+		return 0
+	}
+	return n.Bc20 * (1 - math.Pow(t/n.Tc0, n.N))
+}
+
+// Jc returns the critical current density in amperes per square metre at field b in teslas and temperature t in kelvin.
+// NbTi is isotropic, so theta is ignored.
+func (n *NbTi) Jc(b, theta, t float64) float64 {
+	b = math.Abs(b)
+	bc2 := n.Bc2(t)
+	if b >= bc2 {
+		return 0
+	}
+	if b == 0 {
+		return math.Inf(1)
+	}
+	reduced := b / bc2
+	return n.JcRef * n.C0 / b * math.Pow(reduced, n.Alpha) * math.Pow(1-reduced, n.Beta) *
+		math.Pow(1-math.Pow(t/n.Tc0, n.N), n.Gamma)
+}
+
+// Nb3Sn is the strain dependent scaling of the critical surface of Nb3Sn of Godeke, in the form adopted by ITER in 2008
+//
+//	Jc(B, T, ε) = C/B s(ε) (1 - t^1.52) (1 - t²) b^P (1 - b)^Q
+//
+// where t = T/Tc*(ε), b = B/Bc2*(T, ε), Tc*(ε) = Tc0max s(ε)^(1/3) and Bc2*(T, ε) = Bc20max s(ε) (1 - t^1.52).
+// The strain function is
+//
+//	s(ε) = 1 + (Ca1 (sqrt(εsh² + Eps0a²) - sqrt((ε - εsh)² + Eps0a²)) - Ca2 ε) / (1 - Ca1 Eps0a)
+//
+// with εsh = Ca2 Eps0a / sqrt(Ca1² - Ca2²), so s(0) = 1.
+type Nb3Sn struct {
+	C       float64 // Scaling constant in amperes teslas per square metre
+	Tc0max  float64 // Critical temperature at 0 T and no strain in kelvin
+	Bc20max float64 // Upper critical field at 0 K and no strain in teslas
+	P       float64 // Low field exponent, 0.5 in the scaling of Godeke
+	Q       float64 // High field exponent, 2 in the scaling of Godeke
+	Ca1     float64
+	Ca2     float64
+	Eps0a   float64
+	Strain  float64 // Intrinsic axial strain of the filaments, negative in compression
+}
+
+// NewNb3SnITER creates the ITER-2008 scaling with parameters representative of an ITER TF strand without strain,
+// which gives a non-copper critical current density of about 1 kA/mm² at 12 T and 4.2 K.
+func NewNb3SnITER() *Nb3Sn {
+	return &Nb3Sn{
+		C:       83075e6,
+		Tc0max:  16.06,
+		Bc20max: 32.97,
+		P:       0.63,
+		Q:       2.1,
+		Ca1:     44.48,
+		Ca2:     0,
+		Eps0a:   0.00256,
+	}
+}
+
+// NewNb3SnGodeke creates the scaling of Godeke, with P = 0.5 and Q = 2, for the same strand as NewNb3SnITER.
+// C is chosen so that both scalings agree at 12 T and 4.2 K.
+func NewNb3SnGodeke() *Nb3Sn {
+	n := NewNb3SnITER()
+	ref := n.Jc(12, 0, 4.2)
+	n.P, n.Q = 0.5, 2
+	n.C *= ref / n.Jc(12, 0, 4.2)
+	return n
+}
+
+// StrainFunction returns s(ε) for the Strain of the conductor.
+func (n *Nb3Sn) StrainFunction() float64 {
+	epsSh := n.Ca2 * n.Eps0a / math.Sqrt(n.Ca1*n.Ca1-n.Ca2*n.Ca2)
+	return 1 + (n.Ca1*(math.Hypot(epsSh, n.Eps0a)-math.Hypot(n.Strain-epsSh, n.Eps0a))-n.Ca2*n.Strain)/(1-n.Ca1*n.Eps0a)
+}
+
+// Bc2 returns the upper critical field in teslas at temperature t in kelvin and the Strain of the conductor.
+func (n *Nb3Sn) Bc2(t float64) float64 {
+	s := n.StrainFunction()
+	reduced := t / (n.Tc0max * math.Cbrt(s))
+	if reduced >= 1 {
+		return 0
+	}
+	return n.Bc20max * s * (1 - math.Pow(reduced, 1.52))
+}
+
+// Jc returns the critical current density in amperes per square metre at field b in teslas, temperature t in kelvin
+// and the Strain of the conductor. Nb3Sn is isotropic, so theta is ignored.
+func (n *Nb3Sn) Jc(b, theta, t float64) float64 {
+	b = math.Abs(b)
+	bc2 := n.Bc2(t)
+	if b >= bc2 {
+		return 0
+	}
+	if b == 0 {
+		return math.Inf(1)
+	}
+	s := n.StrainFunction()
+	reduced := t / (n.Tc0max * math.Cbrt(s))
+	bReduced := b / bc2
+	return n.C / b * s * (1 - math.Pow(reduced, 1.52)) * (1 - reduced*reduced) *
+		math.Pow(bReduced, n.P) * math.Pow(1-bReduced, n.Q)
+}
+
+// TabulatedCriticalSurface interpolates a table of the measured critical current of a conductor, such as an HTS tape,
+// over field, angle and temperature.
+type TabulatedCriticalSurface struct {
+	Area float64 // Area in square metres over which the critical current is spread to give the current density
+
+	table *lattice
+}
+
+// NewTabulatedCriticalSurface creates a critical surface from the critical current ic in amperes of a conductor of
+// cross-sectional area in square metres, measured on a grid of field b in teslas, angle theta in radians and
+// temperature t in kelvin. ic is given in grid order, with t varying fastest and b slowest.
+//
+// The current is interpolated linearly. The angle and temperature are held at the edges of the table outside it, while
+// the field is extrapolated linearly so that the critical current falls to 0 at high field.
+// The angle is folded into [0, π/2] before the lookup, so the table only needs to cover that range.
+func NewTabulatedCriticalSurface(b, theta, t Axis, ic []float64, area float64) (*TabulatedCriticalSurface, error) {
+	if n := b.N * theta.N * t.N; len(ic) != n {
+		return nil, fmt.Errorf("table needs %d critical currents, got %d", n, len(ic))
+	}
+	if area <= 0 {
+		return nil, fmt.Errorf("area must be positive, got %g m²", area)
+	}
+	return &TabulatedCriticalSurface{Area: area, table: newLattice([3]Axis{b, theta, t}, ic)}, nil
+}
+
+// Ic returns the critical current in amperes at field b in teslas, angle theta in radians and temperature t in kelvin.
+func (s *TabulatedCriticalSurface) Ic(b, theta, t float64) float64 {
+	theta = math.Mod(math.Abs(theta), math.Pi)
+	if theta > math.Pi/2 {
+		theta = math.Pi - theta
+	}
+	min, max := s.table.bounds()
+	p := [3]float64{
+		math.Abs(b),
+		math.Max(min[1], math.Min(max[1], theta)),
+		math.Max(min[2], math.Min(max[2], t)),
+	}
+	return math.Max(0, s.table.trilinear(p))
+}
+
+// Jc returns the critical current density in amperes per square metre at field b in teslas, angle theta in radians
+// and temperature t in kelvin.
+func (s *TabulatedCriticalSurface) Jc(b, theta, t float64) float64 {
+	return s.Ic(b, theta, t) / s.Area
+}
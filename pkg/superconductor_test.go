@@ -0,0 +1,119 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNbTi(t *testing.T) {
+	n := NewNbTiLHC(3e9)
+
+	// The LHC fit is normalised to the reference current density at 5 T and 4.2 K.
+	if jc := n.Jc(5, 0, 4.2); !approxEqual(jc/3e9, 1, 0.03) {
+		t.Errorf("expected Jc(5 T, 4.2 K) close to 3e9 A/m², got %g", jc)
+	}
+	if bc2 := n.Bc2(1.9); bc2 < 12 || bc2 > 14 {
+		t.Errorf("expected Bc2(1.9 K) of about 13 T, got %g T", bc2)
+	}
+
+	for _, tc := range []struct {
+		name string
+		b, t float64
+	}{
+		{"above_bc2", n.Bc2(4.2) + 0.1, 4.2},
+		{"at_bc2", n.Bc2(4.2), 4.2},
+		{"above_tc", 1, 9.3},
+	} {
+		if jc := n.Jc(tc.b, 0, tc.t); jc != 0 {
+			t.Errorf("%s: expected 0, got %g", tc.name, jc)
+		}
+	}
+
+	// Jc falls with field and with temperature.
+	if n.Jc(6, 0, 4.2) >= n.Jc(5, 0, 4.2) || n.Jc(5, 0, 4.5) >= n.Jc(5, 0, 4.2) {
+		t.Errorf("expected Jc to fall with field and temperature")
+	}
+}
+
+func TestNb3Sn(t *testing.T) {
+	n := NewNb3SnITER()
+
+	if s := n.StrainFunction(); !approxEqual(s, 1, 1e-12) {
+		t.Errorf("expected s(0) = 1, got %g", s)
+	}
+	if jc := n.Jc(12, 0, 4.2); jc < 0.9e9 || jc > 1.2e9 {
+		t.Errorf("expected Jc(12 T, 4.2 K) of about 1e9 A/m², got %g", jc)
+	}
+	if jc := n.Jc(n.Bc2(4.2), 0, 4.2); jc != 0 {
+		t.Errorf("expected 0 at Bc2, got %g", jc)
+	}
+	if jc := n.Jc(1, 0, 16.1); jc != 0 {
+		t.Errorf("expected 0 above Tc, got %g", jc)
+	}
+
+	// Compressive strain lowers s, Bc2 and Jc.
+	unstrained := n.Jc(12, 0, 4.2)
+	n.Strain = -0.003
+	if s := n.StrainFunction(); s >= 1 {
+		t.Errorf("expected s < 1 under compression, got %g", s)
+	}
+	if n.Jc(12, 0, 4.2) >= unstrained {
+		t.Errorf("expected compression to lower Jc")
+	}
+
+	// The Godeke scaling is matched to the ITER scaling at 12 T and 4.2 K.
+	if jc := NewNb3SnGodeke().Jc(12, 0, 4.2); !approxEqual(jc/unstrained, 1, 1e-12) {
+		t.Errorf("expected the Godeke scaling to give %g at 12 T and 4.2 K, got %g", unstrained, jc)
+	}
+}
+
+func TestTabulatedCriticalSurface(t *testing.T) {
+	b := NewAxis(0, 10, 11, true)
+	theta := NewAxis(0, math.Pi/2, 3, true)
+	temp := NewAxis(20, 40, 3, true)
+
+	// Ic = 1000 (1 - B/20) (1 - theta/π) (1 - (T-20)/40), which is linear along every axis.
+	ic := func(b, theta, t float64) float64 {
+		return 1000 * (1 - b/20) * (1 - theta/math.Pi) * (1 - (t-20)/40)
+	}
+	var table []float64
+	for _, bv := range b.Values() {
+		for _, tv := range theta.Values() {
+			for _, tt := range temp.Values() {
+				table = append(table, ic(bv, tv, tt))
+			}
+		}
+	}
+	area := 4e-7
+	s, err := NewTabulatedCriticalSurface(b, theta, temp, table, area)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name        string
+		b, theta, t float64
+		expectedIc  float64
+	}{
+		{"inside", 3.3, 0.4, 27, ic(3.3, 0.4, 27)},
+		{"negative_field", -3.3, 0.4, 27, ic(3.3, 0.4, 27)},
+		{"folded_angle", 3.3, math.Pi - 0.4, 27, ic(3.3, 0.4, 27)},
+		{"extrapolated_field", 15, 0.4, 27, ic(15, 0.4, 27)},
+		{"beyond_zero", 25, 0.4, 27, 0},
+		{"clamped_temperature", 3.3, 0.4, 10, ic(3.3, 0.4, 20)},
+	} {
+		if got := s.Ic(tc.b, tc.theta, tc.t); !approxEqual(got, tc.expectedIc, 1e-9) {
+			t.Errorf("%s: expected Ic %g A, got %g A", tc.name, tc.expectedIc, got)
+		}
+		if got := s.Jc(tc.b, tc.theta, tc.t); !approxEqual(got*area, tc.expectedIc, 1e-9) {
+			t.Errorf("%s: expected Jc %g A/m², got %g A/m²", tc.name, tc.expectedIc/area, got)
+		}
+	}
+
+	if _, err := NewTabulatedCriticalSurface(b, theta, temp, table[1:], area); err == nil {
+		t.Errorf("expected an error for a short table")
+	}
+	if _, err := NewTabulatedCriticalSurface(b, theta, temp, table, 0); err == nil {
+		t.Errorf("expected an error for a non-positive area")
+	}
+}
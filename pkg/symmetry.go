@@ -0,0 +1,120 @@
+package golenoid
+
+import "math"
+
+// This file contains the symmetry aware evaluation path for sources that are axisymmetric about the z-axis.
+// The polar components of the field of such a source only depend on (r, z), so the field is calculated
+// once for each unique (r, z) and shared between every phi.
+
+// AxisymmetricSource is implemented by sources that can report whether their field is symmetric about the z-axis.
+//
+// CalculateFullField and CalculateFieldWithWorkers use the symmetry aware path for sources that report true.
+type AxisymmetricSource interface {
+	FieldSource
+	// Axisymmetric reports whether the field of the source is symmetric about the z-axis of the global frame.
+	Axisymmetric() bool
+}
+
+var (
+	_ AxisymmetricSource = (*Solenoid)(nil)
+	_ AxisymmetricSource = (*Loop)(nil)
+	_ AxisymmetricSource = (*MagnetSystem)(nil)
+	_ AxisymmetricSource = (*AxisymmetricFieldMap)(nil)
+)
+
+// axisymmetricTolerance is the distance in metres within which two values of r or z are treated as equal
+// when looking for unique (r, z), so that cartesian points that share r up to rounding share a calculation.
+// It is also the tolerance used to decide whether a placement keeps the z-axis in place.
+const axisymmetricTolerance = 1e-12
+
+// isAxisymmetric reports whether src implements AxisymmetricSource and is axisymmetric.
+func isAxisymmetric(src FieldSource) bool {
+	a, ok := src.(AxisymmetricSource)
+	return ok && a.Axisymmetric()
+}
+
+// keepsZAxis reports whether the placement maps the z-axis onto itself, in either direction.
+// A nil placement keeps the z-axis.
+func (p *Placement) keepsZAxis() bool {
+	if p == nil {
+		return true
+	}
+	axis := p.Rotation.Apply(Vec3{0, 0, 1})
+	return math.Abs(axis[0]) < axisymmetricTolerance && math.Abs(axis[1]) < axisymmetricTolerance &&
+		math.Abs(p.Translation[0]) < axisymmetricTolerance && math.Abs(p.Translation[1]) < axisymmetricTolerance
+}
+
+// Axisymmetric reports whether the field of the solenoid is symmetric about the z-axis, which is the case unless
+// its Placement moves or tilts its axis away from the z-axis.
+func (s *Solenoid) Axisymmetric() bool {
+	return s.Placement.keepsZAxis()
+}
+
+// Axisymmetric reports whether the field of the loop is symmetric about the z-axis, which is the case unless
+// its Placement moves or tilts its axis away from the z-axis.
+func (l *Loop) Axisymmetric() bool {
+	return l.Placement.keepsZAxis()
+}
+
+// Axisymmetric reports whether every source in the system is axisymmetric about the z-axis.
+func (m *MagnetSystem) Axisymmetric() bool {
+	for _, src := range m.Sources {
+		if !isAxisymmetric(src) {
+			return false
+		}
+	}
+	return true
+}
+
+// Axisymmetric reports whether the field of the solenoid is symmetric about the z-axis.
+func (s *solenoidUsing) Axisymmetric() bool {
+	return s.solenoid.Axisymmetric()
+}
+
+// Axisymmetric always reports true, as the map is built from an axisymmetric source.
+func (m *AxisymmetricFieldMap) Axisymmetric() bool {
+	return true
+}
+
+// CalculateFullFieldAxisymmetric calculates the field of src at every point in field, calculating the field only
+// once for each unique (r, z) and rotating it to every phi. This cuts the cost of a polar grid by a factor of nphi.
+//
+// src must be axisymmetric about the z-axis, otherwise the result is wrong. CalculateFullField already uses this
+// path for sources that implement AxisymmetricSource.
+func CalculateFullFieldAxisymmetric(src FieldSource, field *Field) {
+	field.Source = src
+	fanOutAxisymmetric(src, field, calculateEveryPoint)
+}
+
+// rzKey identifies a unique (r, z) up to axisymmetricTolerance.
+type rzKey [2]int64
+
+func newRZKey(r, z float64) rzKey {
+	return rzKey{int64(math.Round(r / axisymmetricTolerance)), int64(math.Round(z / axisymmetricTolerance))}
+}
+
+// fanOutAxisymmetric calculates the field at one PolarPoint at phi = 0 for every unique (r, z) in field using
+// calculate, then sets the field of every point from the point that shares its (r, z).
+func fanOutAxisymmetric(src FieldSource, field *Field, calculate func(FieldSource, *Field)) {
+	unique := make(map[rzKey]int)
+	owners := make([]int, len(field.Points))
+	reduced := &Field{}
+	for i, fp := range field.Points {
+		r, _, z := fp.GetPolarCoordinates()
+		key := newRZKey(r, z)
+		n, ok := unique[key]
+		if !ok {
+			n = len(reduced.Points)
+			unique[key] = n
+			reduced.Points = append(reduced.Points, NewPolarPoint(r, 0, z))
+		}
+		owners[i] = n
+	}
+
+	calculate(src, reduced)
+
+	for i, fp := range field.Points {
+		// SetFieldPolar rotates Br and Bphi into Bx and By for CartesianPoints.
+		fp.SetFieldPolar(reduced.Points[owners[i]].GetPolarField())
+	}
+}
@@ -0,0 +1,90 @@
+package golenoid
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+// countingSource counts the number of times its field is calculated.
+type countingSource struct {
+	*Solenoid
+	calls atomic.Int64
+}
+
+func (c *countingSource) CalculateFieldAtPointSeq(fp FieldPoint) (Bi, Bj, Bk float64) {
+	c.calls.Add(1)
+	return c.Solenoid.CalculateFieldAtPointSeq(fp)
+}
+
+func TestAxisymmetric(t *testing.T) {
+	tt := []struct {
+		name      string
+		placement *Placement
+		expected  bool
+	}{
+		{name: "no_placement", placement: nil, expected: true},
+		{name: "shifted_along_z", placement: NewPlacement(Vec3{0, 0, 1}, IdentityRotation()), expected: true},
+		{name: "flipped", placement: NewPlacement(Vec3{}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, math.Pi)), expected: true},
+		{name: "shifted_along_x", placement: NewPlacement(Vec3{0.01, 0, 0}, IdentityRotation()), expected: false},
+		{name: "tilted", placement: NewPlacement(Vec3{}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, 0.01)), expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+			s.Placement = tc.placement
+			if actual := s.Axisymmetric(); actual != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, actual)
+			}
+			if actual := NewMagnetSystem(NewLoop(0.1, 1, 0), s).Axisymmetric(); actual != tc.expected {
+				t.Errorf("system: expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCalculateFullFieldAxisymmetric(t *testing.T) {
+	tolerance := 1e-15
+	src := &countingSource{Solenoid: NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)}
+
+	grid := NewCylindricalGrid(NewAxis(0, 0.05, 3, true), NewAxis(0, 2*math.Pi, 8, false), NewAxis(-0.2, 0.2, 5, true))
+	CalculateFullField(src, grid.Field())
+	if calls := src.calls.Load(); calls != 15 {
+		t.Errorf("expected 15 calculations for 15 unique (r, z), got %d", calls)
+	}
+
+	// Cartesian points at the same r but different phi share a calculation too.
+	cartesian := NewField(4)
+	for i := range cartesian.Points {
+		x, y, z := PolarToCartesianCoords(0.04, float64(i)*math.Pi/2+0.3, 0.1)
+		cartesian.Points[i] = NewCartesianPoint(x, y, z)
+	}
+	src.calls.Store(0)
+	CalculateFullField(src, cartesian)
+	if calls := src.calls.Load(); calls != 1 {
+		t.Errorf("expected 1 calculation, got %d", calls)
+	}
+
+	for _, field := range []*Field{grid.Field(), cartesian} {
+		for _, fp := range field.Points {
+			expected := NewCartesianPoint(fp.GetCartesianCoordinates())
+			expected.SetFieldCartesian(src.Solenoid.CalculateFieldAtPointSeq(expected))
+			bx, by, bz := fp.GetCartesianField()
+			if !approxEqual(bx, expected.Bx, tolerance) || !approxEqual(by, expected.By, tolerance) || !approxEqual(bz, expected.Bz, tolerance) {
+				t.Errorf("expected %v, got %v", expected, fp)
+			}
+		}
+	}
+}
+
+func TestCalculateFieldWithWorkersAxisymmetric(t *testing.T) {
+	src := &countingSource{Solenoid: NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)}
+	field := CalculateFieldWithWorkers(src, 0, 0.05, 0, math.Pi, -0.2, 0.2, 3, 4, 5, 3)
+	if calls := src.calls.Load(); calls != 15 {
+		t.Errorf("expected 15 calculations for 15 unique (r, z), got %d", calls)
+	}
+	if len(field.Points) != 60 {
+		t.Errorf("expected 60 points, got %d", len(field.Points))
+	}
+}
@@ -0,0 +1,81 @@
+package golenoid
+
+import (
+	"math"
+	"strings"
+)
+
+// MagnetSystem represents a magnet made of many sources, such as a main coil with correction and shielding coils.
+//
+// The field of the system is the superposition of the fields of every source in it.
+type MagnetSystem struct {
+	Sources []FieldSource
+}
+
+// NewMagnetSystem creates a new MagnetSystem from the given sources.
+func NewMagnetSystem(sources ...FieldSource) *MagnetSystem {
+	return &MagnetSystem{
+		Sources: sources,
+	}
+}
+
+// Add adds sources to the system.
+func (m *MagnetSystem) Add(sources ...FieldSource) {
+	m.Sources = append(m.Sources, sources...)
+}
+
+// CalculateFieldAtPoint calculates the magnetic field at the point fp induced by every source in the system.
+func (m *MagnetSystem) CalculateFieldAtPoint(fp FieldPoint) (Bi, Bj, Bk float64) {
+	for _, src := range m.Sources {
+		bi, bj, bk := src.CalculateFieldAtPoint(fp)
+		Bi += bi
+		Bj += bj
+		Bk += bk
+	}
+	return
+}
+
+// CalculateFieldAtPointSeq calculates the magnetic field at the point fp induced by every source in the system
+// without spawning any goroutines.
+func (m *MagnetSystem) CalculateFieldAtPointSeq(fp FieldPoint) (Bi, Bj, Bk float64) {
+	for _, src := range m.Sources {
+		bi, bj, bk := calculateFieldSeq(src, fp)
+		Bi += bi
+		Bj += bj
+		Bk += bk
+	}
+	return
+}
+
+// CalculateFullField calculates the field of the system at every point in field.
+func (m *MagnetSystem) CalculateFullField(field *Field) {
+	CalculateFullField(m, field)
+}
+
+// BoundingBox returns the corners of an axis aligned box that contains every source in the system.
+//
+// An empty system has a box of zero size at the origin.
+func (m *MagnetSystem) BoundingBox() (min, max Vec3) {
+	if len(m.Sources) == 0 {
+		return
+	}
+	min = Vec3{math.Inf(1), math.Inf(1), math.Inf(1)}
+	max = Vec3{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, src := range m.Sources {
+		sMin, sMax := src.BoundingBox()
+		for i := 0; i < 3; i++ {
+			min[i] = math.Min(min[i], sMin[i])
+			max[i] = math.Max(max[i], sMax[i])
+		}
+	}
+	return
+}
+
+// Description returns a human readable description of the system and every source in it.
+func (m *MagnetSystem) Description() string {
+	descriptions := make([]string, len(m.Sources))
+	for i, src := range m.Sources {
+		descriptions[i] = src.Description()
+	}
+	return "MagnetSystem{" + strings.Join(descriptions, "; ") + "}"
+}
@@ -0,0 +1,59 @@
+package golenoid
+
+import (
+	"testing"
+)
+
+func TestMagnetSystemSuperposition(t *testing.T) {
+	tolerance := 1e-12
+	main := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+	shield := NewSolenoid(0.2, 0.21, 0.3, -50, 0, 20, 1)
+	corrector := NewSolenoid(0.13, 0.14, 0.05, 30, 0.1, 5, 1)
+
+	tt := []struct {
+		name  string
+		point FieldPoint
+	}{
+		{name: "polar_origin", point: NewPolarPoint(0, 0, 0)},
+		{name: "polar_off_axis", point: NewPolarPoint(0.05, 1, 0.12)},
+		{name: "cartesian_off_axis", point: NewCartesianPoint(0.03, -0.04, -0.2)},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var expected [3]float64
+			for _, s := range []*Solenoid{main, shield, corrector} {
+				bi, bj, bk := s.CalculateFieldAtPointSeq(tc.point)
+				expected[0] += bi
+				expected[1] += bj
+				expected[2] += bk
+			}
+
+			system := NewMagnetSystem(main, shield)
+			system.Add(corrector)
+			bi, bj, bk := system.CalculateFieldAtPointSeq(tc.point)
+			for i, actual := range [3]float64{bi, bj, bk} {
+				if !approxEqual(actual, expected[i], tolerance) {
+					t.Errorf("component %d: expected %g, got %g", i, expected[i], actual)
+				}
+			}
+		})
+	}
+}
+
+func TestMagnetSystemCancellation(t *testing.T) {
+	forward := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+	reverse := NewSolenoid(0.1, 0.12, 0.3, -100, 0, 20, 2)
+	system := NewMagnetSystem(forward, reverse)
+
+	field := NewField(2)
+	field.Points[0] = NewPolarPoint(0.02, 0, 0.1)
+	field.Points[1] = NewCartesianPoint(0.02, 0.03, -0.1)
+	system.CalculateFullField(field)
+
+	for _, p := range field.Points {
+		if p.Magnitude() > 1e-15 {
+			t.Errorf("expected the fields to cancel, got %v", p)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains the types used to place sources at arbitrary positions and orientations.
+
+// Vec3 is a vector in 3D cartesian space (x,y,z).
+type Vec3 [3]float64
+
+// Add returns the sum v + u.
+func (v Vec3) Add(u Vec3) Vec3 {
+	return Vec3{v[0] + u[0], v[1] + u[1], v[2] + u[2]}
+}
+
+// Sub returns the difference v - u.
+func (v Vec3) Sub(u Vec3) Vec3 {
+	return Vec3{v[0] - u[0], v[1] - u[1], v[2] - u[2]}
+}
+
+// Scale returns v multiplied by the scalar a.
+func (v Vec3) Scale(a float64) Vec3 {
+	return Vec3{a * v[0], a * v[1], a * v[2]}
+}
+
+// Dot returns the scalar product of v and u.
+func (v Vec3) Dot(u Vec3) float64 {
+	return v[0]*u[0] + v[1]*u[1] + v[2]*u[2]
+}
+
+// Cross returns the vector product v x u.
+func (v Vec3) Cross(u Vec3) Vec3 {
+	return Vec3{
+		v[1]*u[2] - v[2]*u[1],
+		v[2]*u[0] - v[0]*u[2],
+		v[0]*u[1] - v[1]*u[0],
+	}
+}
+
+// Norm returns the length of v.
+func (v Vec3) Norm() float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+// Rotation is a 3x3 rotation matrix that takes vectors from a local frame into the global frame.
+//
+// The zero value is not a valid rotation, use IdentityRotation or one of the constructors.
+type Rotation [3][3]float64
+
+// IdentityRotation returns the rotation that leaves every vector unchanged.
+func IdentityRotation() Rotation {
+	return Rotation{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+}
+
+// NewRotationFromAxisAngle creates a rotation of angle radians about axis, following the right hand rule.
+//
+// The axis does not need to be normalised but must not be the zero vector.
+func NewRotationFromAxisAngle(axis Vec3, angle float64) Rotation {
+	n := axis.Scale(1 / axis.Norm())
+	s, c := math.Sincos(angle / 2)
+	return NewRotationFromQuaternion(c, s*n[0], s*n[1], s*n[2])
+}
+
+// NewRotationFromQuaternion creates a rotation from the quaternion w + xi + yj + zk.
+//
+// The quaternion is normalised before use so it only needs to be non-zero.
+func NewRotationFromQuaternion(w, x, y, z float64) Rotation {
+	n := math.Sqrt(w*w + x*x + y*y + z*z)
+	w, x, y, z = w/n, x/n, y/n, z/n
+	return Rotation{
+		{1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w)},
+		{2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w)},
+		{2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y)},
+	}
+}
+
+// NewRotationFromEuler creates a rotation from roll, pitch and yaw angles in radians.
+//
+// The rotations are about the fixed global axes and are applied in the order
+// roll (about x), then pitch (about y), then yaw (about z), i.e. R = Rz(yaw) Ry(pitch) Rx(roll).
+func NewRotationFromEuler(roll, pitch, yaw float64) Rotation {
+	rx := NewRotationFromAxisAngle(Vec3{1, 0, 0}, roll)
+	ry := NewRotationFromAxisAngle(Vec3{0, 1, 0}, pitch)
+	rz := NewRotationFromAxisAngle(Vec3{0, 0, 1}, yaw)
+	return rz.Mul(ry).Mul(rx)
+}
+
+// Apply rotates v from the local frame into the global frame.
+func (r Rotation) Apply(v Vec3) Vec3 {
+	return Vec3{
+		r[0][0]*v[0] + r[0][1]*v[1] + r[0][2]*v[2],
+		r[1][0]*v[0] + r[1][1]*v[1] + r[1][2]*v[2],
+		r[2][0]*v[0] + r[2][1]*v[1] + r[2][2]*v[2],
+	}
+}
+
+// ApplyInverse rotates v from the global frame into the local frame.
+func (r Rotation) ApplyInverse(v Vec3) Vec3 {
+	return r.Transpose().Apply(v)
+}
+
+// Mul returns the rotation r*q, which applies q first and then r.
+func (r Rotation) Mul(q Rotation) Rotation {
+	var m Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				m[i][j] += r[i][k] * q[k][j]
+			}
+		}
+	}
+	return m
+}
+
+// Transpose returns the transpose of r, which is also its inverse.
+func (r Rotation) Transpose() Rotation {
+	var m Rotation
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m[i][j] = r[j][i]
+		}
+	}
+	return m
+}
+
+// Placement is a rigid-body transformation that positions a source in the global frame.
+//
+// A point in the local frame of the source is taken to the global frame by first rotating it
+// with Rotation and then translating it by Translation.
+type Placement struct {
+	Translation Vec3     // Position of the local origin in the global frame
+	Rotation    Rotation // Orientation of the local axes in the global frame
+}
+
+// NewPlacement creates a new Placement from a translation and a rotation.
+func NewPlacement(translation Vec3, rotation Rotation) *Placement {
+	return &Placement{
+		Translation: translation,
+		Rotation:    rotation,
+	}
+}
+
+// ToLocal transforms the global position v into the local frame.
+func (p *Placement) ToLocal(v Vec3) Vec3 {
+	return p.Rotation.ApplyInverse(v.Sub(p.Translation))
+}
+
+// ToGlobal transforms the local position v into the global frame.
+func (p *Placement) ToGlobal(v Vec3) Vec3 {
+	return p.Rotation.Apply(v).Add(p.Translation)
+}
+
+// transformField evaluates calc at fp after moving it into the local frame and rotates the result back
+// into the global frame.
+//
+// calc is always given a *CartesianPoint and must return cartesian components. The returned field is
+// expressed in the coordinate system of fp, just like the rest of the field calculations.
+func (p *Placement) transformField(fp FieldPoint, calc func(FieldPoint) (float64, float64, float64)) (Bi, Bj, Bk float64) {
+	x, y, z := fp.GetCartesianCoordinates()
+	local := p.ToLocal(Vec3{x, y, z})
+	bx, by, bz := calc(NewCartesianPoint(local[0], local[1], local[2]))
+	b := p.Rotation.Apply(Vec3{bx, by, bz})
+
+	switch fp.(type) {
+	case *CartesianPoint:
+		return b[0], b[1], b[2]
+	case *PolarPoint:
+		_, phi, _ := fp.GetPolarCoordinates()
+		return CartesianToPolarField(b[0], b[1], b[2], phi)
+	default:
+		panic(fmt.Sprintf("Unsupported point type: %T", fp))
+	}
+}
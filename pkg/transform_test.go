@@ -0,0 +1,101 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRotationConstructors(t *testing.T) {
+	tolerance := 1e-12
+
+	tt := []struct {
+		name     string
+		rotation Rotation
+		v        Vec3
+		expected Vec3
+	}{
+		{name: "identity", rotation: IdentityRotation(), v: Vec3{1, 2, 3}, expected: Vec3{1, 2, 3}},
+		{name: "axis_angle_z", rotation: NewRotationFromAxisAngle(Vec3{0, 0, 1}, math.Pi/2), v: Vec3{1, 0, 0}, expected: Vec3{0, 1, 0}},
+		{name: "axis_angle_y", rotation: NewRotationFromAxisAngle(Vec3{0, 2, 0}, math.Pi/2), v: Vec3{0, 0, 1}, expected: Vec3{1, 0, 0}},
+		{name: "quaternion_x", rotation: NewRotationFromQuaternion(math.Cos(math.Pi/4), math.Sin(math.Pi/4), 0, 0), v: Vec3{0, 1, 0}, expected: Vec3{0, 0, 1}},
+		{name: "euler_yaw", rotation: NewRotationFromEuler(0, 0, math.Pi), v: Vec3{1, 0, 0}, expected: Vec3{-1, 0, 0}},
+		{name: "euler_roll_then_yaw", rotation: NewRotationFromEuler(math.Pi/2, 0, math.Pi/2), v: Vec3{0, 1, 0}, expected: Vec3{0, 0, 1}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.rotation.Apply(tc.v)
+			for i := range actual {
+				if !approxEqual(actual[i], tc.expected[i], tolerance) {
+					t.Errorf("expected %v, got %v", tc.expected, actual)
+					break
+				}
+			}
+			back := tc.rotation.ApplyInverse(actual)
+			for i := range back {
+				if !approxEqual(back[i], tc.v[i], tolerance) {
+					t.Errorf("inverse: expected %v, got %v", tc.v, back)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPlacedSolenoidField(t *testing.T) {
+	tolerance := 1e-12
+	aligned := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+
+	tt := []struct {
+		name      string
+		placement *Placement
+		point     Vec3 // Point in the global frame
+		local     Vec3 // The same point in the frame of the aligned solenoid
+		toGlobal  Rotation
+	}{
+		{
+			name:      "translated",
+			placement: NewPlacement(Vec3{0.05, -0.02, 0.4}, IdentityRotation()),
+			point:     Vec3{0.08, 0.01, 0.45},
+			local:     Vec3{0.03, 0.03, 0.05},
+			toGlobal:  IdentityRotation(),
+		},
+		{
+			name:      "axis_along_x",
+			placement: NewPlacement(Vec3{}, NewRotationFromAxisAngle(Vec3{0, 1, 0}, math.Pi/2)),
+			point:     Vec3{0.05, 0.02, -0.03},
+			local:     Vec3{0.03, 0.02, 0.05},
+			toGlobal:  NewRotationFromAxisAngle(Vec3{0, 1, 0}, math.Pi/2),
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			placed := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+			placed.Placement = tc.placement
+
+			bx, by, bz := aligned.CalculateFieldAtPointSeq(NewCartesianPoint(tc.local[0], tc.local[1], tc.local[2]))
+			expected := tc.toGlobal.Apply(Vec3{bx, by, bz})
+
+			bx, by, bz = placed.CalculateFieldAtPointSeq(NewCartesianPoint(tc.point[0], tc.point[1], tc.point[2]))
+			actual := Vec3{bx, by, bz}
+			for i := range actual {
+				if !approxEqual(actual[i], expected[i], tolerance) {
+					t.Errorf("expected %v, got %v", expected, actual)
+					break
+				}
+			}
+
+			r, phi, z := CartesianToPolarCoords(tc.point[0], tc.point[1], tc.point[2])
+			br, bphi, bz := placed.CalculateFieldAtPointSeq(NewPolarPoint(r, phi, z))
+			bx, by, bz = PolarToCartesianField(br, bphi, bz, phi)
+			polar := Vec3{bx, by, bz}
+			for i := range polar {
+				if !approxEqual(polar[i], expected[i], tolerance) {
+					t.Errorf("polar point: expected %v, got %v", expected, polar)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package golenoid
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// This file contains writers for the VTK formats read by ParaView.
+// Positions and field vectors are always written in cartesian components, as VTK expects.
+
+// ErrNotStructured is returned when a structured grid is required but the field does not describe one.
+var ErrNotStructured = errors.New("golenoid: field is not a structured grid")
+
+// vtkVertex is the VTK cell type of a single point.
+const vtkVertex = 1
+
+// vtkDimensions returns the dimensions of the field in the order VTK expects, where the first index varies fastest.
+func (f *Field) vtkDimensions() [3]int {
+	return [3]int{f.Dims[2], f.Dims[1], f.Dims[0]}
+}
+
+// vtkTitle returns a single line title of at most 255 characters, as required by the legacy format.
+func (f *Field) vtkTitle() string {
+	title := []rune("golenoid field map: " + f.sourceDescription())
+	if len(title) > 255 {
+		title = title[:255]
+	}
+	return string(title)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writePositions writes the cartesian position of every point, one point per line.
+func (f *Field) writePositions(w *bufio.Writer) {
+	for _, fp := range f.Points {
+		x, y, z := fp.GetCartesianCoordinates()
+		fmt.Fprintf(w, "%s %s %s\n", formatFloat(x), formatFloat(y), formatFloat(z))
+	}
+}
+
+// writeVectors writes the cartesian field of every point, one point per line.
+func (f *Field) writeVectors(w *bufio.Writer) {
+	for _, fp := range f.Points {
+		Bx, By, Bz := fp.GetCartesianField()
+		fmt.Fprintf(w, "%s %s %s\n", formatFloat(Bx), formatFloat(By), formatFloat(Bz))
+	}
+}
+
+// writeMagnitudes writes the magnitude of the field of every point, one point per line.
+func (f *Field) writeMagnitudes(w *bufio.Writer) {
+	for _, fp := range f.Points {
+		fmt.Fprintln(w, formatFloat(fp.Magnitude()))
+	}
+}
+
+// WriteVTK writes the field to w in the legacy ASCII VTK format, with B as a vector attribute and |B| as the scalar Bmag.
+//
+// A structured field is written as a STRUCTURED_GRID, any other field as an UNSTRUCTURED_GRID of vertices.
+func (f *Field) WriteVTK(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	n := len(f.Points)
+
+	fmt.Fprintln(bw, "# vtk DataFile Version 3.0")
+	fmt.Fprintln(bw, f.vtkTitle())
+	fmt.Fprintln(bw, "ASCII")
+	if f.IsStructured() {
+		dims := f.vtkDimensions()
+		fmt.Fprintln(bw, "DATASET STRUCTURED_GRID")
+		fmt.Fprintf(bw, "DIMENSIONS %d %d %d\n", dims[0], dims[1], dims[2])
+		fmt.Fprintf(bw, "POINTS %d double\n", n)
+		f.writePositions(bw)
+	} else {
+		fmt.Fprintln(bw, "DATASET UNSTRUCTURED_GRID")
+		fmt.Fprintf(bw, "POINTS %d double\n", n)
+		f.writePositions(bw)
+		fmt.Fprintf(bw, "CELLS %d %d\n", n, 2*n)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(bw, "1 %d\n", i)
+		}
+		fmt.Fprintf(bw, "CELL_TYPES %d\n", n)
+		for i := 0; i < n; i++ {
+			fmt.Fprintln(bw, vtkVertex)
+		}
+	}
+
+	fmt.Fprintf(bw, "POINT_DATA %d\n", n)
+	fmt.Fprintln(bw, "VECTORS B double")
+	f.writeVectors(bw)
+	fmt.Fprintln(bw, "SCALARS Bmag double 1")
+	fmt.Fprintln(bw, "LOOKUP_TABLE default")
+	f.writeMagnitudes(bw)
+	return bw.Flush()
+}
+
+// writeXMLPointData writes the PointData element shared by the XML formats.
+func (f *Field) writeXMLPointData(w *bufio.Writer) {
+	fmt.Fprintln(w, `      <PointData Vectors="B" Scalars="Bmag">`)
+	fmt.Fprintln(w, `        <DataArray type="Float64" Name="B" NumberOfComponents="3" format="ascii">`)
+	f.writeVectors(w)
+	fmt.Fprintln(w, `        </DataArray>`)
+	fmt.Fprintln(w, `        <DataArray type="Float64" Name="Bmag" format="ascii">`)
+	f.writeMagnitudes(w)
+	fmt.Fprintln(w, `        </DataArray>`)
+	fmt.Fprintln(w, `      </PointData>`)
+}
+
+// writeXMLPoints writes the Points element shared by the XML formats.
+func (f *Field) writeXMLPoints(w *bufio.Writer) {
+	fmt.Fprintln(w, `      <Points>`)
+	fmt.Fprintln(w, `        <DataArray type="Float64" NumberOfComponents="3" format="ascii">`)
+	f.writePositions(w)
+	fmt.Fprintln(w, `        </DataArray>`)
+	fmt.Fprintln(w, `      </Points>`)
+}
+
+// WriteVTS writes the field to w as an XML VTK structured grid (.vts), with B as a vector attribute and |B| as the scalar Bmag.
+//
+// It returns ErrNotStructured if the field does not lie on a structured grid.
+func (f *Field) WriteVTS(w io.Writer) error {
+	if !f.IsStructured() {
+		return ErrNotStructured
+	}
+	dims := f.vtkDimensions()
+	extent := fmt.Sprintf("0 %d 0 %d 0 %d", dims[0]-1, dims[1]-1, dims[2]-1)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0"?>`)
+	fmt.Fprintln(bw, `<VTKFile type="StructuredGrid" version="0.1" byte_order="LittleEndian">`)
+	fmt.Fprintf(bw, "  <StructuredGrid WholeExtent=\"%s\">\n", extent)
+	fmt.Fprintf(bw, "    <Piece Extent=\"%s\">\n", extent)
+	f.writeXMLPointData(bw)
+	f.writeXMLPoints(bw)
+	fmt.Fprintln(bw, `    </Piece>`)
+	fmt.Fprintln(bw, `  </StructuredGrid>`)
+	fmt.Fprintln(bw, `</VTKFile>`)
+	return bw.Flush()
+}
+
+// WriteVTU writes the field to w as an XML VTK unstructured grid (.vtu) of vertices, with B as a vector attribute
+// and |B| as the scalar Bmag. Unlike WriteVTS it accepts any field.
+func (f *Field) WriteVTU(w io.Writer) error {
+	n := len(f.Points)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0"?>`)
+	fmt.Fprintln(bw, `<VTKFile type="UnstructuredGrid" version="0.1" byte_order="LittleEndian">`)
+	fmt.Fprintln(bw, `  <UnstructuredGrid>`)
+	fmt.Fprintf(bw, "    <Piece NumberOfPoints=\"%d\" NumberOfCells=\"%d\">\n", n, n)
+	f.writeXMLPointData(bw)
+	f.writeXMLPoints(bw)
+	fmt.Fprintln(bw, `      <Cells>`)
+	fmt.Fprintln(bw, `        <DataArray type="Int64" Name="connectivity" format="ascii">`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintln(bw, i)
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `        <DataArray type="Int64" Name="offsets" format="ascii">`)
+	for i := 1; i <= n; i++ {
+		fmt.Fprintln(bw, i)
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `        <DataArray type="UInt8" Name="types" format="ascii">`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintln(bw, vtkVertex)
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `      </Cells>`)
+	fmt.Fprintln(bw, `    </Piece>`)
+	fmt.Fprintln(bw, `  </UnstructuredGrid>`)
+	fmt.Fprintln(bw, `</VTKFile>`)
+	return bw.Flush()
+}
+
+// WriteCoilsVTP writes the winding envelope of each solenoid to w as XML VTK polydata (.vtp).
+//
+// Each winding is drawn as the surface of its rectangular cross-section swept around the axis in nSegments
+// segments, in the global frame. Every layer of a graded winding is drawn separately. The cell data "solenoid" holds the index of the solenoid each face belongs to.
+func WriteCoilsVTP(w io.Writer, nSegments int, solenoids ...*Solenoid) error {
+	if nSegments < 3 {
+		return fmt.Errorf("at least 3 segments are needed to draw a coil, got %d", nSegments)
+	}
+
+	var points []Vec3
+	var polys [][4]int
+	var owners []int
+	for i, s := range solenoids {
+		for _, b := range s.blocks() {
+			// The corners of the cross-section of the block in the (r, z) plane, in order around its edge.
+			zMin, zMax := s.CentrePos+b.z-b.length/2, s.CentrePos+b.z+b.length/2
+			profile := [4][2]float64{
+				{b.aMin, zMin},
+				{b.aMax, zMin},
+				{b.aMax, zMax},
+				{b.aMin, zMax},
+			}
+			first := len(points)
+			for j := 0; j < nSegments; j++ {
+				phi := 2 * math.Pi * float64(j) / float64(nSegments)
+				for _, corner := range profile {
+					x, y, z := PolarToCartesianCoords(corner[0], phi, corner[1])
+					p := Vec3{x, y, z}
+					if s.Placement != nil {
+						p = s.Placement.ToGlobal(p)
+					}
+					points = append(points, p)
+				}
+			}
+			for j := 0; j < nSegments; j++ {
+				next := (j + 1) % nSegments
+				for k := 0; k < 4; k++ {
+					polys = append(polys, [4]int{
+						first + 4*j + k,
+						first + 4*j + (k+1)%4,
+						first + 4*next + (k+1)%4,
+						first + 4*next + k,
+					})
+					owners = append(owners, i)
+				}
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `<?xml version="1.0"?>`)
+	fmt.Fprintln(bw, `<VTKFile type="PolyData" version="0.1" byte_order="LittleEndian">`)
+	fmt.Fprintln(bw, `  <PolyData>`)
+	fmt.Fprintf(bw, "    <Piece NumberOfPoints=\"%d\" NumberOfPolys=\"%d\">\n", len(points), len(polys))
+	fmt.Fprintln(bw, `      <CellData Scalars="solenoid">`)
+	fmt.Fprintln(bw, `        <DataArray type="Int64" Name="solenoid" format="ascii">`)
+	for _, owner := range owners {
+		fmt.Fprintln(bw, owner)
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `      </CellData>`)
+	fmt.Fprintln(bw, `      <Points>`)
+	fmt.Fprintln(bw, `        <DataArray type="Float64" NumberOfComponents="3" format="ascii">`)
+	for _, p := range points {
+		fmt.Fprintf(bw, "%s %s %s\n", formatFloat(p[0]), formatFloat(p[1]), formatFloat(p[2]))
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `      </Points>`)
+	fmt.Fprintln(bw, `      <Polys>`)
+	fmt.Fprintln(bw, `        <DataArray type="Int64" Name="connectivity" format="ascii">`)
+	for _, poly := range polys {
+		fmt.Fprintf(bw, "%d %d %d %d\n", poly[0], poly[1], poly[2], poly[3])
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `        <DataArray type="Int64" Name="offsets" format="ascii">`)
+	for i := range polys {
+		fmt.Fprintln(bw, 4*(i+1))
+	}
+	fmt.Fprintln(bw, `        </DataArray>`)
+	fmt.Fprintln(bw, `      </Polys>`)
+	fmt.Fprintln(bw, `    </Piece>`)
+	fmt.Fprintln(bw, `  </PolyData>`)
+	fmt.Fprintln(bw, `</VTKFile>`)
+	return bw.Flush()
+}
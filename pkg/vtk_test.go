@@ -0,0 +1,111 @@
+package golenoid
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// checkWellFormedXML fails the test if b is not well formed XML.
+func checkWellFormedXML(t *testing.T, b []byte) {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("output is not well formed XML: %v", err)
+		}
+	}
+}
+
+func TestWriteVTK(t *testing.T) {
+	structured := NewFieldGridPolar(0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 3, 5)
+	CalculateFullField(NewLoop(0.2, 10, 0), structured)
+
+	unstructured := NewField(2)
+	unstructured.Points[0] = NewCartesianPoint(0, 0, 0)
+	unstructured.Points[1] = NewCartesianPoint(1, 0, 0)
+
+	tt := []struct {
+		name     string
+		field    *Field
+		expected []string
+	}{
+		{
+			name:     "structured",
+			field:    structured,
+			expected: []string{"DATASET STRUCTURED_GRID", "DIMENSIONS 5 3 3", "POINTS 45 double", "POINT_DATA 45", "VECTORS B double", "SCALARS Bmag double 1"},
+		},
+		{
+			name:     "unstructured",
+			field:    unstructured,
+			expected: []string{"DATASET UNSTRUCTURED_GRID", "POINTS 2 double", "CELLS 2 4", "CELL_TYPES 2", "POINT_DATA 2"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tc.field.WriteVTK(&buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(buf.String(), "\n")
+			if lines[0] != "# vtk DataFile Version 3.0" || lines[2] != "ASCII" {
+				t.Errorf("unexpected header: %q", lines[:3])
+			}
+			for _, e := range tc.expected {
+				if !strings.Contains(buf.String(), e+"\n") {
+					t.Errorf("expected output to contain %q", e)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteVTKXML(t *testing.T) {
+	structured := NewFieldGridPolar(0, 0.1, 0, math.Pi, -0.1, 0.1, 3, 3, 5)
+	CalculateFullField(NewLoop(0.2, 10, 0), structured)
+
+	var buf bytes.Buffer
+	if err := structured.WriteVTS(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkWellFormedXML(t, buf.Bytes())
+	if !strings.Contains(buf.String(), `WholeExtent="0 4 0 2 0 2"`) {
+		t.Errorf("expected the extent of the grid in the output")
+	}
+
+	buf.Reset()
+	if err := structured.WriteVTU(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkWellFormedXML(t, buf.Bytes())
+
+	if err := NewField(3).WriteVTS(&buf); err != ErrNotStructured {
+		t.Errorf("expected ErrNotStructured, got %v", err)
+	}
+}
+
+func TestWriteCoilsVTP(t *testing.T) {
+	tilted := NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2)
+	tilted.Placement = NewPlacement(Vec3{1, 0, 0}, NewRotationFromAxisAngle(Vec3{1, 0, 0}, 0.1))
+
+	var buf bytes.Buffer
+	if err := WriteCoilsVTP(&buf, 8, NewSolenoid(0.1, 0.12, 0.3, 100, 0, 20, 2), tilted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkWellFormedXML(t, buf.Bytes())
+	if !strings.Contains(buf.String(), `NumberOfPoints="64" NumberOfPolys="64"`) {
+		t.Errorf("expected 64 points and 64 polys in the output")
+	}
+
+	if err := WriteCoilsVTP(&buf, 2, tilted); err == nil {
+		t.Errorf("expected an error for too few segments")
+	}
+}
@@ -0,0 +1,154 @@
+package golenoid
+
+import (
+	"fmt"
+	"math"
+)
+
+// This file contains the description of a winding in terms of the physical conductor it is wound from.
+
+// Wire describes the bare conductor and insulation a coil is wound from, either a round wire or a rectangular cable.
+type Wire struct {
+	Diameter   float64 // Bare diameter of a round wire in metres, or 0 for a rectangular cable
+	Width      float64 // Bare axial width of a rectangular cable in metres
+	Height     float64 // Bare radial height of a rectangular cable in metres
+	Insulation float64 // Thickness of the insulation on every side of the conductor in metres
+	Density    float64 // Mass density of the bare conductor in kilograms per cubic metre, e.g. 8960 for copper
+}
+
+// Round reports whether the wire is a round wire rather than a rectangular cable.
+func (w Wire) Round() bool {
+	return w.Diameter > 0
+}
+
+// Area returns the cross-sectional area of the bare conductor in square metres.
+func (w Wire) Area() float64 {
+	if w.Round() {
+		return math.Pi * w.Diameter * w.Diameter / 4
+	}
+	return w.Width * w.Height
+}
+
+// InsulatedSize returns the axial width and radial height of the insulated conductor in metres.
+func (w Wire) InsulatedSize() (width, height float64) {
+	if w.Round() {
+		return w.Diameter + 2*w.Insulation, w.Diameter + 2*w.Insulation
+	}
+	return w.Width + 2*w.Insulation, w.Height + 2*w.Insulation
+}
+
+// Conductor returns the bare conductor as a Conductor for the stress and margin calculations.
+// A round wire is replaced by the square of the same area.
+func (w Wire) Conductor() Conductor {
+	if w.Round() {
+		side := math.Sqrt(w.Area())
+		return Conductor{Width: side, Height: side}
+	}
+	return Conductor{Width: w.Width, Height: w.Height}
+}
+
+// validate returns an error if the dimensions of the wire are not physical.
+func (w Wire) validate() error {
+	if w.Diameter < 0 || w.Insulation < 0 {
+		return fmt.Errorf("wire must have a non-negative diameter and insulation, got %g and %g m", w.Diameter, w.Insulation)
+	}
+	if !w.Round() && (w.Width <= 0 || w.Height <= 0) {
+		return fmt.Errorf("wire must have a positive diameter, or a positive width and height, got %g by %g m", w.Width, w.Height)
+	}
+	return nil
+}
+
+// Winding describes how a solenoid is wound from a Wire.
+type Winding struct {
+	Wire        Wire
+	Rinner      float64 // Radius of the former in metres, on which the first layer is wound
+	Length      float64 // Axial length available for the winding in metres
+	Nlayers     int     // Number of layers
+	LayerSpacer float64 // Thickness of the spacer or insulation between consecutive layers in metres
+
+	// FillFactor is the ratio of the insulated width of the wire to the axial pitch of the turns, which accounts
+	// for the gaps left by an imperfect winding. It is 1 for a perfectly close wound coil, which is assumed if it is 0.
+	FillFactor float64
+}
+
+// NewWoundSolenoid creates a new Solenoid carrying current and centred at centre, whose number of turns, pitch and
+// loop radii follow from the winding, along with a WindingReport of the wire it is wound from.
+//
+// As many turns as fit in the Length of the winding are wound, so the Length of the solenoid is the wound length,
+// which can be slightly shorter. The turns of the first layer touch the former and every layer is separated from
+// the next by the LayerSpacer. Every wound layer is a Layer of a graded solenoid powered in Series, as thick as the
+// insulated wire and with its loops at the centre of the wire, so the winding of the solenoid is the conductor
+// itself and leaves out the spacers between the layers.
+func NewWoundSolenoid(w Winding, current, centre float64) (*Solenoid, WindingReport, error) {
+	if err := w.Wire.validate(); err != nil {
+		return nil, WindingReport{}, err
+	}
+	if w.Nlayers < 1 {
+		return nil, WindingReport{}, fmt.Errorf("winding must have at least 1 layer, got %d", w.Nlayers)
+	}
+	if w.LayerSpacer < 0 {
+		return nil, WindingReport{}, fmt.Errorf("layer spacer must be non-negative, got %g m", w.LayerSpacer)
+	}
+	fill := w.FillFactor
+	if fill == 0 {
+		fill = 1
+	}
+	if fill < 0 || fill > 1 {
+		return nil, WindingReport{}, fmt.Errorf("fill factor must be between 0 and 1, got %g", fill)
+	}
+
+	width, height := w.Wire.InsulatedSize()
+	pitch := width / fill
+	// Allow for rounding when a whole number of turns exactly fills the length.
+	nTurns := int(math.Floor(w.Length/pitch + 1e-9))
+	if nTurns < 1 {
+		return nil, WindingReport{}, fmt.Errorf("not a single turn of pitch %g m fits in the length %g m", pitch, w.Length)
+	}
+
+	layers := make([]Layer, w.Nlayers)
+	for i := range layers {
+		layers[i] = Layer{
+			Radius:    w.Rinner + height/2 + float64(i)*(height+w.LayerSpacer),
+			Thickness: height,
+			Length:    float64(nTurns) * pitch,
+			Nturns:    nTurns,
+			Series:    true,
+		}
+	}
+	s, err := NewGradedSolenoid(current, centre, layers...)
+	if err != nil {
+		return nil, WindingReport{}, err
+	}
+
+	// The cross-section of the winding runs from the former to the outside of the last layer, spacers included.
+	build := float64(w.Nlayers)*height + float64(w.Nlayers-1)*w.LayerSpacer
+	return s, windingReport(layers, w.Wire, build*float64(nTurns)*pitch), nil
+}
+
+// WindingReport summarises the conductor needed to wind a solenoid.
+type WindingReport struct {
+	Turns           int     // Total number of turns of the winding
+	WireLength      float64 // Length of wire in metres
+	ConductorVolume float64 // Volume of the bare conductor in cubic metres
+	Mass            float64 // Mass of the bare conductor in kilograms, 0 if the Wire has no Density
+
+	// FillFactor is the fraction of the cross-section of the winding, spacers included, that is bare conductor.
+	FillFactor float64
+}
+
+// windingReport calculates the length, volume and mass of the wire needed to wind the layers, whose winding has the
+// given cross-section in square metres.
+//
+// Every turn is a turn of a helix with the pitch of its layer, and the short transitions between layers and the
+// leads are neglected.
+func windingReport(layers []Layer, wire Wire, crossSection float64) WindingReport {
+	var report WindingReport
+	for _, l := range layers {
+		report.Turns += l.Nturns
+		report.WireLength += float64(l.Nturns) * math.Hypot(2*math.Pi*l.Radius, l.pitch())
+	}
+	report.ConductorVolume = report.WireLength * wire.Area()
+	report.Mass = report.ConductorVolume * wire.Density
+	report.FillFactor = float64(report.Turns) * wire.Area() / crossSection
+	return report
+}
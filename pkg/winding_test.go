@@ -0,0 +1,103 @@
+package golenoid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewWoundSolenoid(t *testing.T) {
+	tolerance := 1e-12
+	// 1 mm copper wire with 50 µm of enamel, wound in 4 layers with 0.1 mm spacers.
+	copper := Wire{Diameter: 1e-3, Insulation: 5e-5, Density: 8960}
+	w := Winding{Wire: copper, Rinner: 0.05, Length: 0.1, Nlayers: 4, LayerSpacer: 1e-4}
+
+	s, _, err := NewWoundSolenoid(w, 10, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Layers) != 4 || s.CentrePos != 0.2 || s.Current != 10 {
+		t.Fatalf("expected 4 layers at 0.2 m carrying 10 A, got %s", s.Description())
+	}
+	for i, l := range s.windingLayers() {
+		// 90 turns of 1.1 mm fit in 100 mm.
+		if l.Nturns != 90 || !approxEqual(l.Length, 0.099, tolerance) || l.Current != 10 {
+			t.Errorf("layer %d: expected 90 turns over 0.099 m carrying 10 A, got %+v", i, l)
+		}
+		// The first layer touches the former and every layer is one insulated diameter and one spacer further out.
+		if expected := 0.05 + 0.00055 + float64(i)*0.0012; !approxEqual(l.Radius, expected, tolerance) {
+			t.Errorf("layer %d: expected a radius of %g m, got %g m", i, expected, l.Radius)
+		}
+		if !approxEqual(l.Thickness, 0.0011, tolerance) || !approxEqual(l.pitch(), 0.0011, tolerance) {
+			t.Errorf("layer %d: expected a thickness and pitch of 1.1 mm, got %g m and %g m", i, l.Thickness, l.pitch())
+		}
+	}
+
+	// The winding is the conductor, from the former to the outside of the last layer.
+	if rMin, rMax, zMin, zMax := s.envelope(); !approxEqual(rMin, 0.05, tolerance) || !approxEqual(rMax, 0.0547, tolerance) ||
+		!approxEqual(zMin, -0.0495, tolerance) || !approxEqual(zMax, 0.0495, tolerance) {
+		t.Errorf("expected the winding to span 0.05 to 0.0547 m and -0.0495 to 0.0495 m, got %g to %g m and %g to %g m",
+			rMin, rMax, zMin, zMax)
+	}
+
+	// A loosely wound coil fits fewer turns.
+	w.FillFactor = 0.9
+	if _, loose, _ := NewWoundSolenoid(w, 10, 0); loose.Turns != 4*81 {
+		t.Errorf("expected 81 turns per layer with a fill factor of 0.9, got %d in total", loose.Turns)
+	}
+
+	// A rectangular cable exactly filling the length.
+	cable := Winding{Wire: Wire{Width: 2e-3, Height: 1e-3, Insulation: 5e-4}, Rinner: 0.1, Length: 0.3, Nlayers: 2}
+	if s, report, err := NewWoundSolenoid(cable, 100, 0); err != nil || report.Turns != 200 || !approxEqual(s.Layers[1].Radius, 0.103, tolerance) {
+		t.Errorf("expected 100 turns per layer with the outer layer at 0.103 m, got %+v, %+v and %v", s, report, err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		w    Winding
+	}{
+		{"no_wire", Winding{Rinner: 0.1, Length: 0.1, Nlayers: 1}},
+		{"no_layers", Winding{Wire: copper, Rinner: 0.1, Length: 0.1}},
+		{"too_short", Winding{Wire: copper, Rinner: 0.1, Length: 1e-3, Nlayers: 1}},
+		{"negative_spacer", Winding{Wire: copper, Rinner: 0.1, Length: 0.1, Nlayers: 1, LayerSpacer: -1}},
+		{"fill_factor", Winding{Wire: copper, Rinner: 0.1, Length: 0.1, Nlayers: 1, FillFactor: 1.5}},
+	} {
+		if _, _, err := NewWoundSolenoid(tc.w, 1, 0); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestWindingReport(t *testing.T) {
+	relTolerance := 1e-9
+	copper := Wire{Diameter: 1e-3, Insulation: 5e-5, Density: 8960}
+	_, report, err := NewWoundSolenoid(Winding{Wire: copper, Rinner: 0.05, Length: 0.1, Nlayers: 4, LayerSpacer: 1e-4}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var length float64
+	for i := 0; i < 4; i++ {
+		length += 90 * math.Hypot(2*math.Pi*(0.05055+float64(i)*0.0012), 0.0011)
+	}
+	area := math.Pi * 1e-6 / 4
+
+	for _, tc := range []struct {
+		name             string
+		expected, actual float64
+	}{
+		{"turns", 360, float64(report.Turns)},
+		{"wire_length", length, report.WireLength},
+		{"conductor_volume", length * area, report.ConductorVolume},
+		{"mass", length * area * 8960, report.Mass},
+		// The winding is 4 wires and 3 spacers deep and 90 wires long.
+		{"fill_factor", 360 * area / (4.7e-3 * 0.099), report.FillFactor},
+	} {
+		if !approxEqual(tc.actual/tc.expected, 1, relTolerance) {
+			t.Errorf("%s: expected %g, got %g", tc.name, tc.expected, tc.actual)
+		}
+	}
+
+	if c := copper.Conductor(); !approxEqual(c.Area(), area, 1e-18) {
+		t.Errorf("expected the conductor to have the area of the wire, got %g", c.Area())
+	}
+}